@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bnb-chain/token-recover-approver/internal/app/signer"
+	"github.com/bnb-chain/token-recover-approver/pkg/util"
+)
+
+var signerCmd = &cobra.Command{
+	Use:   "signer",
+	Short: "Run the signer daemon",
+	Long: "signer loads the key configured in secret and signs approval digests for the approver " +
+		"over a unix socket, so the http facing process never holds key material.",
+	Run: func(_ *cobra.Command, _ []string) {
+		signer, err := signer.Initialize(cfgFile)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		util.Launch(signer.Start, signer.Stop, time.Duration(timeout)*time.Second)
+	},
+}
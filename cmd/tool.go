@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/bnb-chain/node/app"
 	"github.com/bnb-chain/token-recover-approver/internal/app/tool"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/spf13/cobra"
 )
 
@@ -49,6 +53,14 @@ var verifyDataFromFullnodeCmd = &cobra.Command{
 			fmt.Println("home path is required")
 			os.Exit(1)
 		}
+		opts := &tool.VerifyOptions{
+			VerifyMerkleRoot: verifyMerkleRoot,
+			ContinueOnError:  verifyContinueOnError,
+			ReportPath:       verifyReportPath,
+			ReportFormat:     verifyReportFormat,
+			Workers:          verifyWorkers,
+			ReversePass:      verifyReversePass,
+		}
 
 		tool, err := tool.Initialize(cfgFile)
 		if err != nil {
@@ -56,7 +68,10 @@ var verifyDataFromFullnodeCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		err = tool.VerifyDataFromFullnode(nodeCtx, home, verifyMerkleRoot)
+		summary, err := tool.VerifyDataFromFullnode(nodeCtx, home, opts)
+		if summary != nil {
+			summary.Print(os.Stdout)
+		}
 		if err != nil {
 			fmt.Println(err.Error())
 			os.Exit(1)
@@ -66,18 +81,331 @@ var verifyDataFromFullnodeCmd = &cobra.Command{
 	},
 }
 
+var signRequestCmd = &cobra.Command{
+	Use:   "sign-request",
+	Short: "sign an approval request offline",
+	Long: "sign-request runs the approval pipeline for a request read from a file or stdin " +
+		"against a local proofs file, and writes the signed approval JSON",
+	Run: func(cmd *cobra.Command, args []string) {
+		// the configured store is only opened if no proofs file is given
+		initialize := tool.Initialize
+		if signRequestProofPath != "" {
+			initialize = tool.InitializeWithoutStore
+		}
+		tool, err := initialize(cfgFile)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		err = tool.SignRequest(signRequestPath, signRequestProofPath, signRequestOutputPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var verifyApprovalCmd = &cobra.Command{
+	Use:   "verify-approval",
+	Short: "verify a signed approval",
+	Long: "verify-approval recomputes the signed digest of an approval written by sign-request " +
+		"and recovers the signer addresses",
+	Run: func(cmd *cobra.Command, args []string) {
+		approvers := make([]common.Address, 0, len(verifyApprovalApprovers))
+		for _, approver := range verifyApprovalApprovers {
+			if !common.IsHexAddress(approver) {
+				fmt.Printf("invalid approver address: %s\n", approver)
+				os.Exit(1)
+			}
+			approvers = append(approvers, common.HexToAddress(approver))
+		}
+
+		tool, err := tool.InitializeWithoutStore(cfgFile)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		signers, err := tool.VerifyApproval(verifyApprovalPath, approvers)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		for _, signer := range signers {
+			fmt.Printf("signed by %s\n", signer.Hex())
+		}
+		fmt.Println("verify approval successfully!")
+	},
+}
+
+var ownerSignCmd = &cobra.Command{
+	Use:   "owner-sign",
+	Short: "sign a token recover request as the owner",
+	Long: "owner-sign signs the token recover request with the owner's BC mnemonic or private key, " +
+		"the amount is looked up from the configured store, and prints the approval request body. " +
+		"The key is read from stdin if neither --mnemonic nor --private_key is given",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(ownerSignTokenSymbol) == 0 {
+			fmt.Println("token_symbol is required")
+			os.Exit(1)
+		}
+		if !common.IsHexAddress(ownerSignClaimAddress) {
+			fmt.Printf("invalid claim address: %s\n", ownerSignClaimAddress)
+			os.Exit(1)
+		}
+
+		key := &tool.OwnerKey{
+			Mnemonic:   ownerSignMnemonic,
+			PrivateKey: ownerSignPrivateKey,
+			Account:    ownerSignAccount,
+			Index:      ownerSignIndex,
+		}
+		if key.Mnemonic == "" && key.PrivateKey == "" {
+			secret, err := tool.ReadOwnerKey()
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+			// a mnemonic has several words, a private key has none
+			if strings.Contains(secret, " ") {
+				key.Mnemonic = secret
+			} else {
+				key.PrivateKey = secret
+			}
+		}
+
+		tool, err := tool.Initialize(cfgFile)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		req, err := tool.OwnerSign(key, ownerSignTokenSymbol, common.HexToAddress(ownerSignClaimAddress))
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		body, err := json.Marshal(req)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		fmt.Println(string(body))
+	},
+}
+
+var buildMerkleTreeCmd = &cobra.Command{
+	Use:   "build-merkle-tree",
+	Short: "build merkle tree from accounts snapshot",
+	Long: "build-merkle-tree builds the merkle tree of an accounts snapshot, " +
+		"writes the merkle proofs file and prints the merkle root",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(buildMerkleTreeAccountsPath) == 0 || len(buildMerkleTreeOutputPath) == 0 {
+			fmt.Println("accounts_path and output are required")
+			os.Exit(1)
+		}
+
+		// the tree is built from the snapshot, the configured store is not opened
+		tool, err := tool.InitializeWithoutStore(cfgFile)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		root, err := tool.BuildMerkleTree(buildMerkleTreeAccountsPath, buildMerkleTreeOutputPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Printf("merkle root: %s\n", hexutil.Encode(root))
+	},
+}
+
+var exportSnapshotCmd = &cobra.Command{
+	Use:   "export-snapshot",
+	Short: "export accounts snapshot from fullnode",
+	Long: "export-snapshot writes every non-escrow account of the fullnode database with its coins " +
+		"to the accounts snapshot format read by build-merkle-tree",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(home) == 0 || len(exportSnapshotOutputPath) == 0 {
+			fmt.Println("home and output are required")
+			os.Exit(1)
+		}
+
+		// the snapshot is read from the fullnode, the configured store is not opened
+		tool, err := tool.InitializeWithoutStore(cfgFile)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		totals, err := tool.ExportSnapshot(nodeCtx, home, exportSnapshotOutputPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		if exportSnapshotTotals {
+			for _, total := range totals {
+				fmt.Printf("%s\t%s\t%d\n", total.Denom, total.Amount, total.Holders)
+			}
+		}
+	},
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "diff two proofs snapshots",
+	Long: "diff compares the account assets of two proofs snapshots, each is a proofs file or the store of a config file, " +
+		"and reports the assets added, removed or changed in amount with the net amount of every denom",
+	Run: func(cmd *cobra.Command, args []string) {
+		if diffFormat != tool.TextOutputFormat && diffFormat != tool.JSONOutputFormat {
+			fmt.Println("format must be text or json")
+			os.Exit(1)
+		}
+		oldSrc := tool.DiffSource{ProofPath: diffOldPath, ConfigPath: diffOldConfigPath}
+		newSrc := tool.DiffSource{ProofPath: diffNewPath, ConfigPath: diffNewConfigPath}
+
+		// the configured store is only opened if a side uses it, and it is only read
+		initialize := tool.InitializeWithoutMigration
+		if !oldSrc.IsConfiguredStore() && !newSrc.IsConfiguredStore() {
+			initialize = tool.InitializeWithoutStore
+		}
+		tool, err := initialize(cfgFile)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		diff, err := tool.Diff(oldSrc, newSrc)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		if err := diff.Write(os.Stdout, diffFormat); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "recoverable supply statistics",
+	Long: "stats streams the configured store or a proofs file and prints the holders and the amount of every denom " +
+		"with its largest holders, and the number of proofs of every depth",
+	Run: func(cmd *cobra.Command, args []string) {
+		if statsFormat != tool.TextOutputFormat && statsFormat != tool.JSONOutputFormat {
+			fmt.Println("format must be text or json")
+			os.Exit(1)
+		}
+
+		// the configured store is only opened if no proofs file is given, and it is only read
+		initialize := tool.InitializeWithoutMigration
+		if statsProofPath != "" {
+			initialize = tool.InitializeWithoutStore
+		}
+		tool, err := initialize(cfgFile)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		stats, err := tool.Stats(statsProofPath, statsTop)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		if err := stats.Write(os.Stdout, statsFormat); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
 var (
 	migrationFromLocalToSQLConfigPath string
 
 	home             string
 	verifyMerkleRoot bool
 
+	verifyContinueOnError bool
+	verifyReportPath      string
+	verifyReportFormat    string
+	verifyWorkers         int
+	verifyReversePass     bool
+
 	nodeCtx = app.ServerContext
+
+	signRequestPath       string
+	signRequestProofPath  string
+	signRequestOutputPath string
+
+	verifyApprovalPath      string
+	verifyApprovalApprovers []string
+
+	ownerSignMnemonic     string
+	ownerSignPrivateKey   string
+	ownerSignAccount      uint32
+	ownerSignIndex        uint32
+	ownerSignTokenSymbol  string
+	ownerSignClaimAddress string
+
+	buildMerkleTreeAccountsPath string
+	buildMerkleTreeOutputPath   string
+
+	exportSnapshotOutputPath string
+	exportSnapshotTotals     bool
+
+	diffOldPath       string
+	diffOldConfigPath string
+	diffNewPath       string
+	diffNewConfigPath string
+	diffFormat        string
+
+	statsProofPath string
+	statsTop       int
+	statsFormat    string
 )
 
 func init() {
 	migrationFromLocalToSQLCmd.Flags().StringVar(&migrationFromLocalToSQLConfigPath, "proof_path", "", "proof file path")
 	verifyDataFromFullnodeCmd.Flags().StringVar(&home, "home", app.DefaultNodeHome, "directory for config and data")
 	verifyDataFromFullnodeCmd.Flags().BoolVar(&verifyMerkleRoot, "verify_merkle_root", false, "verify merkle root")
-	toolCmd.AddCommand(migrationFromLocalToSQLCmd, verifyDataFromFullnodeCmd)
+	verifyDataFromFullnodeCmd.Flags().BoolVar(&verifyContinueOnError, "continue_on_error", false, "keep verifying after a discrepancy and report the extra proofs in store")
+	verifyDataFromFullnodeCmd.Flags().StringVar(&verifyReportPath, "report", "", "discrepancy report file path")
+	verifyDataFromFullnodeCmd.Flags().StringVar(&verifyReportFormat, "report_format", "json", "discrepancy report format, json or csv")
+	verifyDataFromFullnodeCmd.Flags().IntVar(&verifyWorkers, "workers", 1, "number of workers verifying accounts concurrently")
+	verifyDataFromFullnodeCmd.Flags().BoolVar(&verifyReversePass, "reverse", false, "check every proof in store against its chain account after the accounts are verified")
+	signRequestCmd.Flags().StringVar(&signRequestPath, "request", "-", "approval request file, - for stdin")
+	signRequestCmd.Flags().StringVar(&signRequestProofPath, "proof_path", "", "proof file path, the configured store is used if empty")
+	signRequestCmd.Flags().StringVar(&signRequestOutputPath, "output", "-", "signed approval file, - for stdout")
+	verifyApprovalCmd.Flags().StringVar(&verifyApprovalPath, "approval", "-", "signed approval file, - for stdin")
+	verifyApprovalCmd.Flags().StringSliceVar(&verifyApprovalApprovers, "approvers", nil, "expected approver addresses, the configured signers if empty")
+	ownerSignCmd.Flags().StringVar(&ownerSignMnemonic, "mnemonic", "", "owner BC mnemonic")
+	ownerSignCmd.Flags().StringVar(&ownerSignPrivateKey, "private_key", "", "owner BC private key in hex")
+	ownerSignCmd.Flags().Uint32Var(&ownerSignAccount, "account", 0, "account of the HD path m/44'/714'/account'/0/index")
+	ownerSignCmd.Flags().Uint32Var(&ownerSignIndex, "index", 0, "address index of the HD path m/44'/714'/account'/0/index")
+	ownerSignCmd.Flags().StringVar(&ownerSignTokenSymbol, "token_symbol", "", "token symbol to recover")
+	ownerSignCmd.Flags().StringVar(&ownerSignClaimAddress, "claim_address", "", "BSC address to receive the recovered token")
+	buildMerkleTreeCmd.Flags().StringVar(&buildMerkleTreeAccountsPath, "accounts_path", "", "accounts snapshot file path")
+	buildMerkleTreeCmd.Flags().StringVar(&buildMerkleTreeOutputPath, "output", "", "merkle proofs file path")
+	exportSnapshotCmd.Flags().StringVar(&home, "home", app.DefaultNodeHome, "directory for config and data")
+	exportSnapshotCmd.Flags().StringVar(&exportSnapshotOutputPath, "output", "", "accounts snapshot file path")
+	exportSnapshotCmd.Flags().BoolVar(&exportSnapshotTotals, "totals", false, "print the total amount and holders of every denom")
+	diffCmd.Flags().StringVar(&diffOldPath, "old", "", "old proofs file path")
+	diffCmd.Flags().StringVar(&diffOldConfigPath, "old_config", "", "config file of the old store, the configured store is used if old and old_config are empty")
+	diffCmd.Flags().StringVar(&diffNewPath, "new", "", "new proofs file path")
+	diffCmd.Flags().StringVar(&diffNewConfigPath, "new_config", "", "config file of the new store, the configured store is used if new and new_config are empty")
+	diffCmd.Flags().StringVar(&diffFormat, "format", "text", "output format, text or json")
+	statsCmd.Flags().StringVar(&statsProofPath, "proof_path", "", "proof file path, the configured store is used if empty")
+	statsCmd.Flags().IntVar(&statsTop, "top", 10, "number of largest holders of every denom")
+	statsCmd.Flags().StringVar(&statsFormat, "format", "text", "output format, text or json")
+	toolCmd.AddCommand(migrationFromLocalToSQLCmd, verifyDataFromFullnodeCmd, signRequestCmd, verifyApprovalCmd, ownerSignCmd, buildMerkleTreeCmd, exportSnapshotCmd, diffCmd, statsCmd)
 }
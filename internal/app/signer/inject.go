@@ -0,0 +1,42 @@
+package signer
+
+import (
+	"github.com/rs/zerolog"
+
+	"github.com/bnb-chain/token-recover-approver/internal/config"
+	"github.com/bnb-chain/token-recover-approver/internal/version"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/unixsocket"
+)
+
+type Signer struct {
+	logger *zerolog.Logger
+	config *config.Config
+	server *unixsocket.Server
+}
+
+func (signer *Signer) Start() error {
+	signer.logger.Info().Str("app_version", version.AppVersion).Str("git_commit", version.GitCommit).Str("git_commit_date", version.GitCommitDate).Msg("version info")
+	signer.logger.Info().Msgf("signer listen %s", signer.config.Signer.SocketPath)
+	return signer.server.ListenAndServe()
+}
+
+func (signer *Signer) Stop() error {
+	signer.logger.Info().Msg("shutdown signer ...")
+	if err := signer.server.Close(); err != nil {
+		return err
+	}
+	signer.logger.Info().Msg("signer is closed")
+	return nil
+}
+
+func newSigner(
+	logger *zerolog.Logger,
+	config *config.Config,
+	server *unixsocket.Server,
+) *Signer {
+	return &Signer{
+		logger: logger,
+		config: config,
+		server: server,
+	}
+}
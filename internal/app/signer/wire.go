@@ -0,0 +1,23 @@
+//go:build wireinject
+// +build wireinject
+
+//The build tag makes sure the stub is not built in the final build.
+
+package signer
+
+import (
+	"github.com/google/wire"
+
+	"github.com/bnb-chain/token-recover-approver/internal/config"
+	"github.com/bnb-chain/token-recover-approver/internal/injection"
+)
+
+func Initialize(configPath string) (*Signer, error) {
+	wire.Build(
+		newSigner,
+		config.NewConfig,
+		injection.InitLogger,
+		injection.InitSignerServer,
+	)
+	return &Signer{}, nil
+}
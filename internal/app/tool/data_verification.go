@@ -29,110 +29,251 @@ const (
 )
 
 var (
-	ErrEmptyState = errors.New("empty state")
+	ErrEmptyState         = errors.New("empty state")
+	ErrVerificationFailed = errors.New("verification failed")
 )
 
-func (tool *Tool) VerifyDataFromFullnode(nodeCtx *config.BNBBeaconChainContext, home string, verifyMerkleRoot bool) error {
-	emptyState, err := isEmptyState(home)
+// VerifyOptions configures VerifyDataFromFullnode.
+type VerifyOptions struct {
+	VerifyMerkleRoot bool
+	// ContinueOnError keeps verifying after a discrepancy, and reports the proofs in the store without chain account at the end
+	ContinueOnError bool
+	// ReportPath is the file every discrepancy is written to in ReportFormat, no report is written if empty
+	ReportPath   string
+	ReportFormat string
+	// Workers is the number of accounts verified concurrently, the results are the same as a sequential verification
+	Workers int
+	// ReversePass checks every proof in the store against its chain account after the accounts are verified,
+	// and reports the reason of every extra proof
+	ReversePass bool
+}
+
+// accountLookup returns the coins of the chain account, found is false if there is no such account.
+type accountLookup func(addr sdk.AccAddress) (coins sdk.Coins, found bool)
+
+func (tool *Tool) VerifyDataFromFullnode(nodeCtx *config.BNBBeaconChainContext, home string, opts *VerifyOptions) (*VerificationSummary, error) {
+	dapp, appCtx, err := openApp(nodeCtx, home)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if emptyState {
-		return ErrEmptyState
+
+	escrowAccs := escrowAccs(tool.logger)
+	iterate := func(fn func(addr sdk.AccAddress, coins sdk.Coins) bool) {
+		dapp.AccountKeeper.IterateAccounts(appCtx, func(acc sdk.Account) (stop bool) {
+			namedAcc := acc.(nodetypes.NamedAccount)
+			addr := namedAcc.GetAddress()
+			if _, matched := escrowAccs[addr.String()]; matched {
+				tool.logger.Info().Msg("skip escrow account: " + addr.String())
+				return false
+			}
+			coins := make(sdk.Coins, 0)
+			for _, coin := range accountCoins(namedAcc) {
+				if coin.Amount > 0 {
+					coins = append(coins, coin)
+				}
+			}
+			return !fn(addr, coins)
+		})
 	}
-	db, err := openDB(home)
-	if err != nil {
-		return err
+	lookup := func(addr sdk.AccAddress) (sdk.Coins, bool) {
+		acc := dapp.AccountKeeper.GetAccount(appCtx, addr)
+		if acc == nil {
+			return nil, false
+		}
+		return accountCoins(acc.(nodetypes.NamedAccount)), true
 	}
-	viper.Set("home", home)
-	ctx := nodeCtx.ToCosmosServerCtx()
-	dapp := app.NewBNBBeaconChain(ctx.Logger, db, io.Discard)
-	appCtx := dapp.NewContext(sdk.RunTxModeCheck, abci.Header{})
+	return tool.verifyData(iterate, lookup, escrowAccs, opts)
+}
 
+// verifyData verifies the coins of the iterated chain accounts against the store, then the proofs of the store
+// against the chain accounts returned by lookup if opts.ReversePass is set.
+func (tool *Tool) verifyData(iterate accountIterator, lookup accountLookup, escrowAccs map[string]struct{}, opts *VerifyOptions) (*VerificationSummary, error) {
 	totalInStore, err := tool.store.CountAccountAssetProofs()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	escrowAccs := escrowAccs(tool.logger)
-	count := int64(0)
+	report, err := newReportWriter(opts.ReportPath, opts.ReportFormat)
+	if err != nil {
+		return nil, err
+	}
+	summary := newVerificationSummary(totalInStore)
+	record := func(d *Discrepancy) error {
+		tool.logger.Error().
+			Str("address", d.Address).
+			Str("symbol", d.Denom).
+			Int64("expected", d.ChainAmount).
+			Int64("actual", d.StoreAmount).
+			Str("reason", d.Reason).
+			Msg(string(d.Type))
+		summary.add(d)
+		return report.Write(d)
+	}
+	// the proofs found for chain coins, the others in the store are extra, unless the reverse pass looks them up
+	var found map[string]struct{}
+	if opts.ContinueOnError && !opts.ReversePass {
+		found = make(map[string]struct{})
+	}
+	// the amount mismatches reported from the chain, which the reverse pass doesn't report again
+	mismatches := make(map[string]struct{})
+
 	merkleRoot := util.MustDecodeHexToBytes(tool.config.MerkleRoot)
 	ticker := time.NewTicker(displayProcessInterval)
 	defer ticker.Stop()
-	dapp.AccountKeeper.IterateAccounts(appCtx, func(acc sdk.Account) (stop bool) {
+	var iterErr error
+	verify := func(addr sdk.AccAddress, coin sdk.Coin) (*Discrepancy, error) {
+		return tool.verifyCoin(addr, coin, merkleRoot, opts.VerifyMerkleRoot)
+	}
+	tool.verifyAccounts(iterate, opts.Workers, verify, func(res *accountResult) bool {
 		select {
 		case <-ticker.C:
 			tool.logger.Info().
-				Str("process", fmt.Sprintf("%d", count*100/totalInStore)+"%").
+				Str("process", fmt.Sprintf("%d", summary.Verified*100/totalInStore)+"%").
 				Int64("total", totalInStore).
-				Int64("count", count).Msg("verifying accounts")
+				Int64("count", summary.Verified).
+				Int64("failed", summary.Failed()).Msg("verifying accounts")
 		default:
 		}
+		summary.Accounts++
 
-		namedAcc := acc.(nodetypes.NamedAccount)
-		addr := namedAcc.GetAddress()
-		if _, matched := escrowAccs[addr.String()]; matched {
-			tool.logger.Info().Msg("skip escrow account: " + addr.String())
-			return false
+		for _, coinRes := range res.coins {
+			if coinRes.err != nil {
+				iterErr = coinRes.err
+				return false
+			}
+			discrepancy := coinRes.discrepancy
+			if found != nil && (discrepancy == nil || discrepancy.Type != MissingProof) {
+				found[proofKey(res.addr, coinRes.coin.Denom)] = struct{}{}
+			}
+			if discrepancy != nil {
+				if discrepancy.Type == AmountMismatch {
+					mismatches[discrepancy.key()] = struct{}{}
+				}
+				if iterErr = record(discrepancy); iterErr != nil || !opts.ContinueOnError {
+					return false
+				}
+				continue
+			}
+			summary.Verified++
 		}
+		return true
+	})
+	// the store is only checked once every account is verified
+	completed := iterErr == nil && (opts.ContinueOnError || summary.Failed() == 0)
+	if completed && opts.ReversePass {
+		iterErr = tool.verifyStoreAgainstChain(lookup, escrowAccs, func(d *Discrepancy) (bool, error) {
+			if _, reported := mismatches[d.key()]; reported {
+				return true, nil
+			}
+			return opts.ContinueOnError, record(d)
+		})
+	} else if completed && opts.ContinueOnError {
+		iterErr = tool.store.Iterate(func(proof *store.Proof) error {
+			if _, ok := found[proofKey(proof.Address, proof.Denom)]; ok {
+				return nil
+			}
+			return record(&Discrepancy{Type: ExtraProof, Address: proof.Address.String(), Denom: proof.Denom, StoreAmount: proof.Amount})
+		})
+	}
+	if err := report.Close(); err != nil && iterErr == nil {
+		iterErr = err
+	}
+	if iterErr != nil {
+		return summary, iterErr
+	}
+
+	if failed := summary.Failed(); failed > 0 {
+		return summary, fmt.Errorf("%w: %d discrepancies", ErrVerificationFailed, failed)
+	}
+	if summary.Verified != totalInStore {
+		return summary, fmt.Errorf("account mismatch: %d != %d", summary.Verified, totalInStore)
+	}
 
-		coins := namedAcc.GetCoins()
-		frozenCoins := namedAcc.GetFrozenCoins()
-		lockedCoins := namedAcc.GetLockedCoins()
+	return summary, nil
+}
 
-		allCoins := coins.Plus(frozenCoins)
-		allCoins = allCoins.Plus(lockedCoins)
+// verifyCoin compares the chain coin of the account with its proof in the store,
+// it returns the discrepancy if they don't match.
+func (tool *Tool) verifyCoin(addr sdk.AccAddress, coin sdk.Coin, merkleRoot []byte, verifyMerkleRoot bool) (*Discrepancy, error) {
+	discrepancy := &Discrepancy{Address: addr.String(), Denom: coin.Denom, ChainAmount: coin.Amount}
+	proof, err := tool.store.GetAccountAssetProof(addr, coin.Denom)
+	if errors.Is(err, store.ErrProofNotFound) {
+		discrepancy.Type = MissingProof
+		return discrepancy, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	discrepancy.StoreAmount = proof.Amount
 
-		for _, coin := range allCoins {
-			if coin.Amount > 0 {
-				proof, err := tool.store.GetAccountAssetProof(addr, coin.Denom)
-				if err != nil {
-					tool.logger.Error().Str("address", addr.String()).Str("symbol", coin.Denom).Msg("proof not found")
-					return true
-				}
+	if coin.Amount != proof.Amount {
+		discrepancy.Type = AmountMismatch
+		return discrepancy, nil
+	}
 
-				if coin.Amount != proof.Amount {
-					tool.logger.Error().
-						Str("address", addr.String()).
-						Str("symbol", coin.Denom).
-						Int64("expected", coin.Amount).
-						Int64("actual", proof.Amount).
-						Msg("amount mismatch")
-					return true
-				}
+	if verifyMerkleRoot {
+		// verify merkle proof
+		leaf := store.Proof{Address: addr, Denom: coin.Denom, Amount: coin.Amount}
+		leafHash, err := leaf.Serialize()
+		if err != nil {
+			discrepancy.Type = InvalidMerkleProof
+			discrepancy.Reason = "merkle proof serialization failed: " + err.Error()
+			return discrepancy, nil
+		}
+		if !util.VerifyMerkleProof(merkleRoot, proof.Proof, leafHash) {
+			discrepancy.Type = InvalidMerkleProof
+			return discrepancy, nil
+		}
+	}
+	return nil, nil
+}
 
-				if verifyMerkleRoot {
-					// verify merkle proof
-					leaf := store.Proof{Address: addr, Denom: coin.Denom, Amount: coin.Amount}
-					leafHash, err := leaf.Serialize()
-					if err != nil {
-						tool.logger.Error().
-							Str("address", addr.String()).
-							Str("symbol", coin.Denom).
-							Int64("amount", coin.Amount).
-							Msg("merkle proof serialization failed")
-						return true
-					}
-					if !util.VerifyMerkleProof(merkleRoot, proof.Proof, leafHash) {
-						tool.logger.Error().
-							Str("address", addr.String()).
-							Str("symbol", coin.Denom).
-							Int64("amount", coin.Amount).
-							Msg("merkle proof verification failed")
-						return true
-					}
-				}
-				count++
+// verifyStoreAgainstChain looks up the chain account of every proof in the store, and reports the proofs
+// of escrow accounts, of accounts or denoms not on chain, and of amounts which differ from the chain.
+// handle records the discrepancy and returns whether to continue.
+func (tool *Tool) verifyStoreAgainstChain(lookup accountLookup, escrowAccs map[string]struct{}, handle func(d *Discrepancy) (bool, error)) error {
+	errStop := errors.New("stop")
+	var checked int64
+	err := tool.store.Iterate(func(proof *store.Proof) error {
+		checked++
+		if checked%100000 == 0 {
+			tool.logger.Info().Int64("count", checked).Msg("verifying store against chain")
+		}
+
+		discrepancy := &Discrepancy{Address: proof.Address.String(), Denom: proof.Denom, StoreAmount: proof.Amount}
+		if _, matched := escrowAccs[discrepancy.Address]; matched {
+			discrepancy.Type, discrepancy.Reason = ExtraProof, EscrowAccountReason
+		} else if coins, found := lookup(proof.Address); !found {
+			discrepancy.Type, discrepancy.Reason = ExtraProof, AccountNotFoundReason
+		} else {
+			discrepancy.ChainAmount = coins.AmountOf(proof.Denom)
+			switch {
+			case discrepancy.ChainAmount == 0:
+				discrepancy.Type, discrepancy.Reason = ExtraProof, DenomNotHeldReason
+			case discrepancy.ChainAmount != proof.Amount:
+				discrepancy.Type = AmountMismatch
+			default:
+				return nil
 			}
 		}
 
-		return false
+		next, err := handle(discrepancy)
+		if err != nil {
+			return err
+		}
+		if !next {
+			return errStop
+		}
+		return nil
 	})
-
-	if count != totalInStore {
-		return fmt.Errorf("account mismatch: %d != %d", count, totalInStore)
+	tool.logger.Info().Int64("count", checked).Msg("store verified against chain")
+	if errors.Is(err, errStop) {
+		return nil
 	}
+	return err
+}
 
-	return nil
+func proofKey(addr sdk.AccAddress, denom string) string {
+	return string(addr) + ":" + denom
 }
 
 // Escrow Accounts
@@ -181,6 +322,35 @@ func escrowAccs(logger *zerolog.Logger) map[string]struct{} {
 	return escrowAccs
 }
 
+// accountCoins sums the free, frozen and locked coins of the account.
+func accountCoins(acc nodetypes.NamedAccount) sdk.Coins {
+	coins := acc.GetCoins()
+	frozenCoins := acc.GetFrozenCoins()
+	lockedCoins := acc.GetLockedCoins()
+
+	allCoins := coins.Plus(frozenCoins)
+	return allCoins.Plus(lockedCoins)
+}
+
+// openApp opens the BC application state of the fullnode home for reading.
+func openApp(nodeCtx *config.BNBBeaconChainContext, home string) (*app.BNBBeaconChain, sdk.Context, error) {
+	emptyState, err := isEmptyState(home)
+	if err != nil {
+		return nil, sdk.Context{}, err
+	}
+	if emptyState {
+		return nil, sdk.Context{}, ErrEmptyState
+	}
+	db, err := openDB(home)
+	if err != nil {
+		return nil, sdk.Context{}, err
+	}
+	viper.Set("home", home)
+	ctx := nodeCtx.ToCosmosServerCtx()
+	dapp := app.NewBNBBeaconChain(ctx.Logger, db, io.Discard)
+	return dapp, dapp.NewContext(sdk.RunTxModeCheck, abci.Header{}), nil
+}
+
 func isEmptyState(home string) (bool, error) {
 	files, err := os.ReadDir(path.Join(home, "data"))
 	if err != nil {
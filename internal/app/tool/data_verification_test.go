@@ -0,0 +1,152 @@
+package tool
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+
+	"github.com/bnb-chain/token-recover-approver/internal/config"
+	"github.com/bnb-chain/token-recover-approver/internal/store/memory"
+)
+
+// makeVerificationTool creates a tool over a memory store holding the proofs.
+func makeVerificationTool(t *testing.T, proofs memory.Proofs) *Tool {
+	t.Helper()
+	data, err := json.Marshal(proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "proofs.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	proofStore, err := memory.NewMemoryStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Tool{logger: &zerolog.Logger{}, config: &config.Config{MerkleRoot: "0x00"}, store: proofStore}
+}
+
+func TestVerifyData_ReversePass(t *testing.T) {
+	var (
+		held     = sdk.AccAddress("held-account-0000000")
+		changed  = sdk.AccAddress("changed-account-0000")
+		missing  = sdk.AccAddress("missing-account-0000")
+		escrow   = sdk.AccAddress("escrow-account-00000")
+		accounts = map[string]sdk.Coins{
+			held.String():    {{Denom: "BNB", Amount: 100}},
+			changed.String(): {{Denom: "BNB", Amount: 50}},
+		}
+	)
+	tool := makeVerificationTool(t, memory.Proofs{
+		{Address: held, Coin: sdk.Coin{Denom: "BNB", Amount: 100}},
+		{Address: held, Coin: sdk.Coin{Denom: "XYZ-000", Amount: 7}},
+		{Address: changed, Coin: sdk.Coin{Denom: "BNB", Amount: 40}},
+		{Address: missing, Coin: sdk.Coin{Denom: "BNB", Amount: 5}},
+		{Address: escrow, Coin: sdk.Coin{Denom: "BNB", Amount: 10}},
+	})
+	iterate := func(fn func(addr sdk.AccAddress, coins sdk.Coins) bool) {
+		for _, addr := range []sdk.AccAddress{held, changed} {
+			if !fn(addr, accounts[addr.String()]) {
+				return
+			}
+		}
+	}
+	lookup := func(addr sdk.AccAddress) (sdk.Coins, bool) {
+		coins, found := accounts[addr.String()]
+		return coins, found
+	}
+	escrowAccs := map[string]struct{}{escrow.String(): {}}
+
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	opts := &VerifyOptions{ContinueOnError: true, ReversePass: true, ReportPath: reportPath, ReportFormat: JSONReportFormat, Workers: 2}
+	summary, err := tool.verifyData(iterate, lookup, escrowAccs, opts)
+	if !errors.Is(err, ErrVerificationFailed) {
+		t.Fatalf("expected ErrVerificationFailed, got %v", err)
+	}
+	if summary.Accounts != 2 || summary.Verified != 1 || summary.TotalInStore != 5 || summary.Failed() != 4 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*Discrepancy
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].key() < got[j].key() })
+	// the amount mismatch is reported by the chain pass only, not again by the reverse pass
+	want := []*Discrepancy{
+		{Type: AmountMismatch, Address: changed.String(), Denom: "BNB", ChainAmount: 50, StoreAmount: 40},
+		{Type: ExtraProof, Address: escrow.String(), Denom: "BNB", StoreAmount: 10, Reason: EscrowAccountReason},
+		{Type: ExtraProof, Address: held.String(), Denom: "XYZ-000", StoreAmount: 7, Reason: DenomNotHeldReason},
+		{Type: ExtraProof, Address: missing.String(), Denom: "BNB", StoreAmount: 5, Reason: AccountNotFoundReason},
+	}
+	sort.Slice(want, func(i, j int) bool { return want[i].key() < want[j].key() })
+	if !reflect.DeepEqual(got, want) {
+		for _, d := range got {
+			t.Logf("got %+v", d)
+		}
+		t.Fatalf("unexpected discrepancies")
+	}
+}
+
+func TestVerifyStoreAgainstChain(t *testing.T) {
+	var (
+		held    = sdk.AccAddress("held-account-0000000")
+		missing = sdk.AccAddress("missing-account-0000")
+		escrow  = sdk.AccAddress("escrow-account-00000")
+	)
+	tests := []struct {
+		name  string
+		proof *memory.Proof
+		want  *Discrepancy
+	}{
+		{"matching", &memory.Proof{Address: held, Coin: sdk.Coin{Denom: "BNB", Amount: 100}}, nil},
+		{"escrow account", &memory.Proof{Address: escrow, Coin: sdk.Coin{Denom: "BNB", Amount: 10}},
+			&Discrepancy{Type: ExtraProof, Address: escrow.String(), Denom: "BNB", StoreAmount: 10, Reason: EscrowAccountReason}},
+		{"account not found", &memory.Proof{Address: missing, Coin: sdk.Coin{Denom: "BNB", Amount: 5}},
+			&Discrepancy{Type: ExtraProof, Address: missing.String(), Denom: "BNB", StoreAmount: 5, Reason: AccountNotFoundReason}},
+		{"denom not held", &memory.Proof{Address: held, Coin: sdk.Coin{Denom: "XYZ-000", Amount: 7}},
+			&Discrepancy{Type: ExtraProof, Address: held.String(), Denom: "XYZ-000", StoreAmount: 7, Reason: DenomNotHeldReason}},
+		{"amount mismatch", &memory.Proof{Address: held, Coin: sdk.Coin{Denom: "BNB", Amount: 90}},
+			&Discrepancy{Type: AmountMismatch, Address: held.String(), Denom: "BNB", ChainAmount: 100, StoreAmount: 90}},
+	}
+	lookup := func(addr sdk.AccAddress) (sdk.Coins, bool) {
+		if addr.Equals(held) {
+			return sdk.Coins{{Denom: "BNB", Amount: 100}}, true
+		}
+		return nil, false
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := makeVerificationTool(t, memory.Proofs{tt.proof})
+			var got []*Discrepancy
+			err := tool.verifyStoreAgainstChain(lookup, map[string]struct{}{escrow.String(): {}}, func(d *Discrepancy) (bool, error) {
+				got = append(got, d)
+				return true, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == nil {
+				if len(got) != 0 {
+					t.Fatalf("unexpected discrepancies %+v", got)
+				}
+				return
+			}
+			if len(got) != 1 || *got[0] != *tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package tool
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/token-recover-approver/internal/config"
+	"github.com/bnb-chain/token-recover-approver/internal/injection"
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+	"github.com/bnb-chain/token-recover-approver/internal/store/memory"
+	"github.com/bnb-chain/token-recover-approver/pkg/util"
+)
+
+// ChangeType is the kind of change of an account asset between two snapshots.
+type ChangeType string
+
+const (
+	AddedChange   ChangeType = "added"
+	RemovedChange ChangeType = "removed"
+	AmountChange  ChangeType = "changed"
+)
+
+// Output formats of the tool results
+const (
+	TextOutputFormat = "text"
+	JSONOutputFormat = "json"
+)
+
+// DiffSource is a proofs file or the store configured in a config file,
+// the store of the tool is used if both are empty.
+type DiffSource struct {
+	ProofPath  string
+	ConfigPath string
+}
+
+func (src DiffSource) String() string {
+	switch {
+	case src.ProofPath != "":
+		return src.ProofPath
+	case src.ConfigPath != "":
+		return "store of " + src.ConfigPath
+	default:
+		return "configured store"
+	}
+}
+
+// IsConfiguredStore reports whether the source is the store of the tool.
+func (src DiffSource) IsConfiguredStore() bool {
+	return src.ProofPath == "" && src.ConfigPath == ""
+}
+
+// proofSource streams the proofs of a snapshot, store.Store is a proofSource.
+type proofSource interface {
+	Iterate(fn func(proof *store.Proof) error) error
+}
+
+// proofFile streams a proofs file in the format read by the memory store, without loading it.
+type proofFile string
+
+func (path proofFile) Iterate(fn func(proof *store.Proof) error) error {
+	stream := util.NewJSONStream(func() any {
+		return &memory.Proof{}
+	})
+	go stream.Start(string(path))
+
+	var err error
+	for data := range stream.Watch() {
+		// the stream is drained after an error, so that it is closed
+		if err != nil {
+			continue
+		}
+		if data.Error != nil {
+			err = data.Error
+			continue
+		}
+		proof := data.Data.(*memory.Proof)
+		err = fn(&store.Proof{
+			Address: proof.Address,
+			Denom:   proof.Coin.Denom,
+			Amount:  proof.Coin.Amount,
+			Proof:   proof.Proof,
+		})
+	}
+	return err
+}
+
+// openSource returns the proofs of the source and the func closing it.
+func (tool *Tool) openSource(src DiffSource) (proofSource, func() error, error) {
+	if src.ProofPath != "" && src.ConfigPath != "" {
+		return nil, nil, fmt.Errorf("both proofs file %s and config %s are set", src.ProofPath, src.ConfigPath)
+	}
+	if src.ProofPath != "" {
+		return proofFile(src.ProofPath), func() error { return nil }, nil
+	}
+	if src.IsConfiguredStore() {
+		return tool.store, func() error { return nil }, nil
+	}
+	storeConfig, err := config.NewConfig(src.ConfigPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the store is only read, its database is not migrated
+	proofStore, err := injection.InitStoreWithoutMigration(storeConfig, tool.logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proofStore, proofStore.Close, nil
+}
+
+// Change is an account asset which differs between two snapshots.
+type Change struct {
+	Type      ChangeType `json:"type"`
+	Address   string     `json:"address"`
+	Denom     string     `json:"denom"`
+	OldAmount int64      `json:"old_amount"`
+	NewAmount int64      `json:"new_amount"`
+
+	addr sdk.AccAddress
+}
+
+// Duplicate is an account asset with more than one proof in a snapshot, Source is old or new.
+type Duplicate struct {
+	Source  string `json:"source"`
+	Address string `json:"address"`
+	Denom   string `json:"denom"`
+	Amount  int64  `json:"amount"`
+
+	addr sdk.AccAddress
+}
+
+// DenomChange sums the changes of a denom, NetAmount is the new total minus the old total.
+type DenomChange struct {
+	Denom     string   `json:"denom"`
+	Added     int64    `json:"added"`
+	Removed   int64    `json:"removed"`
+	Changed   int64    `json:"changed"`
+	NetAmount *big.Int `json:"net_amount"`
+}
+
+// SnapshotDiff is the difference between two snapshots, sorted by address and denom.
+// The first proof of a duplicated asset is compared, the next ones are listed in Duplicates.
+type SnapshotDiff struct {
+	Old        int64          `json:"old"`
+	New        int64          `json:"new"`
+	Changes    []*Change      `json:"changes"`
+	Duplicates []*Duplicate   `json:"duplicates"`
+	Denoms     []*DenomChange `json:"denoms"`
+}
+
+// Diff compares the account assets of two snapshots.
+func (tool *Tool) Diff(oldSrc, newSrc DiffSource) (*SnapshotDiff, error) {
+	oldSource, closeOld, err := tool.openSource(oldSrc)
+	if err != nil {
+		return nil, fmt.Errorf("open old snapshot: %w", err)
+	}
+	defer closeOld()
+	newSource, closeNew, err := tool.openSource(newSrc)
+	if err != nil {
+		return nil, fmt.Errorf("open new snapshot: %w", err)
+	}
+	defer closeNew()
+	tool.logger.Info().Str("old", oldSrc.String()).Str("new", newSrc.String()).Msg("diff snapshots")
+	return tool.diffProofs(oldSource, newSource)
+}
+
+// diffProofs holds the assets of the old snapshot in memory while the new one is streamed.
+func (tool *Tool) diffProofs(oldSource, newSource proofSource) (*SnapshotDiff, error) {
+	diff := &SnapshotDiff{Changes: make([]*Change, 0), Duplicates: make([]*Duplicate, 0)}
+	addDuplicate := func(source string, proof *store.Proof) {
+		diff.Duplicates = append(diff.Duplicates, &Duplicate{Source: source, addr: proof.Address, Denom: proof.Denom, Amount: proof.Amount})
+	}
+	// the proofs are not kept, only the assets are compared
+	oldAssets := make(map[string]*Change)
+	err := oldSource.Iterate(func(proof *store.Proof) error {
+		diff.Old++
+		key := proofKey(proof.Address, proof.Denom)
+		if _, ok := oldAssets[key]; ok {
+			addDuplicate("old", proof)
+			return nil
+		}
+		oldAssets[key] = &Change{Type: RemovedChange, addr: proof.Address, Denom: proof.Denom, OldAmount: proof.Amount}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read old snapshot: %w", err)
+	}
+	tool.logger.Info().Int64("proofs", diff.Old).Msg("old snapshot loaded")
+
+	// the keys of the new snapshot already compared, the old assets left are removed
+	newKeys := make(map[string]struct{})
+	err = newSource.Iterate(func(proof *store.Proof) error {
+		diff.New++
+		key := proofKey(proof.Address, proof.Denom)
+		if _, ok := newKeys[key]; ok {
+			addDuplicate("new", proof)
+			return nil
+		}
+		newKeys[key] = struct{}{}
+		old, ok := oldAssets[key]
+		if !ok {
+			diff.Changes = append(diff.Changes, &Change{Type: AddedChange, addr: proof.Address, Denom: proof.Denom, NewAmount: proof.Amount})
+			return nil
+		}
+		delete(oldAssets, key)
+		if old.OldAmount != proof.Amount {
+			old.Type = AmountChange
+			old.NewAmount = proof.Amount
+			diff.Changes = append(diff.Changes, old)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read new snapshot: %w", err)
+	}
+	tool.logger.Info().Int64("proofs", diff.New).Msg("new snapshot compared")
+
+	// the assets left are not in the new snapshot
+	for _, old := range oldAssets {
+		diff.Changes = append(diff.Changes, old)
+	}
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		if c := bytes.Compare(diff.Changes[i].addr, diff.Changes[j].addr); c != 0 {
+			return c < 0
+		}
+		return diff.Changes[i].Denom < diff.Changes[j].Denom
+	})
+	sort.SliceStable(diff.Duplicates, func(i, j int) bool {
+		if c := bytes.Compare(diff.Duplicates[i].addr, diff.Duplicates[j].addr); c != 0 {
+			return c < 0
+		}
+		return diff.Duplicates[i].Denom < diff.Duplicates[j].Denom
+	})
+	for _, duplicate := range diff.Duplicates {
+		duplicate.Address = duplicate.addr.String()
+	}
+	if len(diff.Duplicates) > 0 {
+		tool.logger.Warn().Int("duplicates", len(diff.Duplicates)).Msg("snapshots have duplicated assets")
+	}
+
+	denoms := make(map[string]*DenomChange)
+	for _, change := range diff.Changes {
+		change.Address = change.addr.String()
+		denom, ok := denoms[change.Denom]
+		if !ok {
+			denom = &DenomChange{Denom: change.Denom, NetAmount: new(big.Int)}
+			denoms[change.Denom] = denom
+		}
+		switch change.Type {
+		case AddedChange:
+			denom.Added++
+		case RemovedChange:
+			denom.Removed++
+		case AmountChange:
+			denom.Changed++
+		}
+		denom.NetAmount.Add(denom.NetAmount, big.NewInt(change.NewAmount))
+		denom.NetAmount.Sub(denom.NetAmount, big.NewInt(change.OldAmount))
+	}
+	diff.Denoms = make([]*DenomChange, 0, len(denoms))
+	for _, denom := range denoms {
+		diff.Denoms = append(diff.Denoms, denom)
+	}
+	sort.Slice(diff.Denoms, func(i, j int) bool {
+		return diff.Denoms[i].Denom < diff.Denoms[j].Denom
+	})
+	return diff, nil
+}
+
+// Write writes the diff in the format.
+func (diff *SnapshotDiff) Write(w io.Writer, format string) error {
+	switch format {
+	case TextOutputFormat:
+		diff.Print(w)
+		return nil
+	case JSONOutputFormat:
+		return encodeJSON(w, diff)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// Print writes the diff as text, every change, the duplicated assets and then the changes of every denom.
+func (diff *SnapshotDiff) Print(w io.Writer) {
+	for _, change := range diff.Changes {
+		switch change.Type {
+		case AddedChange:
+			fmt.Fprintf(w, "+ %s %s %d\n", change.Address, change.Denom, change.NewAmount)
+		case RemovedChange:
+			fmt.Fprintf(w, "- %s %s %d\n", change.Address, change.Denom, change.OldAmount)
+		case AmountChange:
+			fmt.Fprintf(w, "~ %s %s %d -> %d\n", change.Address, change.Denom, change.OldAmount, change.NewAmount)
+		}
+	}
+	for _, duplicate := range diff.Duplicates {
+		fmt.Fprintf(w, "! %s %s %s %d\n", duplicate.Source, duplicate.Address, duplicate.Denom, duplicate.Amount)
+	}
+	fmt.Fprintf(w, "old: %d, new: %d, changes: %d, duplicates: %d\n", diff.Old, diff.New, len(diff.Changes), len(diff.Duplicates))
+	for _, denom := range diff.Denoms {
+		fmt.Fprintf(w, "%s\tadded %d\tremoved %d\tchanged %d\tnet %s\n", denom.Denom, denom.Added, denom.Removed, denom.Changed, signed(denom.NetAmount))
+	}
+}
+
+func signed(amount *big.Int) string {
+	if amount.Sign() > 0 {
+		return "+" + amount.String()
+	}
+	return amount.String()
+}
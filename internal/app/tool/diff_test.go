@@ -0,0 +1,130 @@
+package tool
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+)
+
+type proofList []*store.Proof
+
+func (proofs proofList) Iterate(fn func(proof *store.Proof) error) error {
+	for _, proof := range proofs {
+		if err := fn(proof); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestDiffProofs(t *testing.T) {
+	alice := sdk.AccAddress(bytes.Repeat([]byte{1}, sdk.AddrLen))
+	bob := sdk.AccAddress(bytes.Repeat([]byte{2}, sdk.AddrLen))
+	oldProofs := proofList{
+		{Address: bob, Denom: "BNB", Amount: 100},
+		{Address: alice, Denom: "BNB", Amount: 50},
+		{Address: alice, Denom: "ABC-123", Amount: 7},
+	}
+	newProofs := proofList{
+		{Address: alice, Denom: "BNB", Amount: 80},
+		{Address: alice, Denom: "ABC-123", Amount: 7},
+		{Address: bob, Denom: "XYZ-456", Amount: 3},
+	}
+
+	tool := &Tool{logger: &zerolog.Logger{}}
+	diff, err := tool.diffProofs(oldProofs, newProofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.Old != 3 || diff.New != 3 {
+		t.Fatalf("got old %d new %d, want 3 and 3", diff.Old, diff.New)
+	}
+
+	want := []Change{
+		{Type: AmountChange, Address: alice.String(), Denom: "BNB", OldAmount: 50, NewAmount: 80},
+		{Type: RemovedChange, Address: bob.String(), Denom: "BNB", OldAmount: 100},
+		{Type: AddedChange, Address: bob.String(), Denom: "XYZ-456", NewAmount: 3},
+	}
+	if len(diff.Changes) != len(want) {
+		t.Fatalf("got %d changes, want %d", len(diff.Changes), len(want))
+	}
+	for i, change := range diff.Changes {
+		change.addr = nil
+		if !reflect.DeepEqual(*change, want[i]) {
+			t.Errorf("change %d is %+v, want %+v", i, *change, want[i])
+		}
+	}
+
+	if len(diff.Denoms) != 2 {
+		t.Fatalf("got %d denoms, want 2", len(diff.Denoms))
+	}
+	bnb, xyz := diff.Denoms[0], diff.Denoms[1]
+	if bnb.Denom != "BNB" || bnb.Changed != 1 || bnb.Removed != 1 || bnb.NetAmount.Int64() != -70 {
+		t.Errorf("unexpected BNB change %+v", bnb)
+	}
+	if xyz.Denom != "XYZ-456" || xyz.Added != 1 || xyz.NetAmount.Int64() != 3 {
+		t.Errorf("unexpected XYZ-456 change %+v", xyz)
+	}
+
+	var out strings.Builder
+	if err := diff.Write(&out, TextOutputFormat); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "BNB\tadded 0\tremoved 1\tchanged 1\tnet -70\n") {
+		t.Errorf("unexpected text output:\n%s", out.String())
+	}
+}
+
+func TestDiffProofs_Duplicates(t *testing.T) {
+	alice := sdk.AccAddress(bytes.Repeat([]byte{1}, sdk.AddrLen))
+	bob := sdk.AccAddress(bytes.Repeat([]byte{2}, sdk.AddrLen))
+	oldProofs := proofList{
+		{Address: alice, Denom: "BNB", Amount: 50},
+		{Address: alice, Denom: "BNB", Amount: 60},
+	}
+	newProofs := proofList{
+		{Address: alice, Denom: "BNB", Amount: 50},
+		{Address: bob, Denom: "BNB", Amount: 10},
+		{Address: alice, Denom: "BNB", Amount: 70},
+		{Address: bob, Denom: "BNB", Amount: 20},
+	}
+
+	tool := &Tool{logger: &zerolog.Logger{}}
+	diff, err := tool.diffProofs(oldProofs, newProofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first proofs are compared, the duplicates are neither added nor removed
+	if len(diff.Changes) != 1 || diff.Changes[0].Type != AddedChange || diff.Changes[0].NewAmount != 10 {
+		t.Fatalf("unexpected changes %+v", diff.Changes)
+	}
+
+	want := []Duplicate{
+		{Source: "old", Address: alice.String(), Denom: "BNB", Amount: 60},
+		{Source: "new", Address: alice.String(), Denom: "BNB", Amount: 70},
+		{Source: "new", Address: bob.String(), Denom: "BNB", Amount: 20},
+	}
+	if len(diff.Duplicates) != len(want) {
+		t.Fatalf("got %d duplicates, want %d", len(diff.Duplicates), len(want))
+	}
+	for i, duplicate := range diff.Duplicates {
+		duplicate.addr = nil
+		if !reflect.DeepEqual(*duplicate, want[i]) {
+			t.Errorf("duplicate %d is %+v, want %+v", i, *duplicate, want[i])
+		}
+	}
+
+	var out strings.Builder
+	if err := diff.Write(&out, TextOutputFormat); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "! new "+bob.String()+" BNB 20\n") || !strings.Contains(out.String(), "duplicates: 3\n") {
+		t.Errorf("unexpected text output:\n%s", out.String())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/bnb-chain/token-recover-approver/internal/config"
+	"github.com/bnb-chain/token-recover-approver/internal/injection"
 	"github.com/bnb-chain/token-recover-approver/internal/store"
 )
 
@@ -25,3 +26,12 @@ func newTool(
 		store:  store,
 	}
 }
+
+// newToolWithoutStore creates a tool for the commands which read a proof file instead of the configured store.
+func newToolWithoutStore(
+	logger *zerolog.Logger,
+	config *config.Config,
+) *Tool {
+	injection.InitSDK(config, logger)
+	return newTool(logger, config, nil)
+}
@@ -0,0 +1,89 @@
+package tool
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/bnb-chain/token-recover-approver/internal/module/approval"
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+	"github.com/bnb-chain/token-recover-approver/internal/store/memory"
+	"github.com/bnb-chain/token-recover-approver/pkg/merkle"
+	"github.com/bnb-chain/token-recover-approver/pkg/util"
+)
+
+// BuildMerkleTree builds the merkle tree of every coin of the accounts snapshot,
+// writes the proofs file read by the memory store and returns the merkle root.
+// The leaves are encoded by store.Proof.Serialize and every proof is verified against the root before it is written.
+func (tool *Tool) BuildMerkleTree(accountsPath, outputPath string) ([]byte, error) {
+	stream := util.NewJSONStream(func() any {
+		return &memory.Account{}
+	})
+	go stream.Start(accountsPath)
+
+	proofs := make(memory.Proofs, 0)
+	for data := range stream.Watch() {
+		if data.Error != nil {
+			return nil, data.Error
+		}
+		account := data.Data.(*memory.Account)
+		for _, coin := range account.Coins {
+			if coin.Amount < 0 {
+				return nil, fmt.Errorf("negative amount of %s: %s", account.Address, coin)
+			}
+			if coin.Amount == 0 {
+				continue
+			}
+			proofs = append(proofs, &memory.Proof{Address: account.Address, Coin: coin})
+		}
+	}
+	// the proofs file is sorted by address and denom
+	sort.Slice(proofs, func(i, j int) bool {
+		if c := bytes.Compare(proofs[i].Address, proofs[j].Address); c != 0 {
+			return c < 0
+		}
+		return proofs[i].Coin.Denom < proofs[j].Coin.Denom
+	})
+	// an address:denom pair is a single leaf, a duplicate would make the claim ambiguous
+	for i := 1; i < len(proofs); i++ {
+		if proofs[i].Address.Equals(proofs[i-1].Address) && proofs[i].Coin.Denom == proofs[i-1].Coin.Denom {
+			return nil, fmt.Errorf("duplicate coin %s of %s", proofs[i].Coin.Denom, proofs[i].Address)
+		}
+	}
+
+	leaves := make([][]byte, 0, len(proofs))
+	for _, proof := range proofs {
+		leaf, err := leafOf(proof.Address, proof.Coin)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, leaf)
+	}
+	tree, err := merkle.NewTree(leaves)
+	if err != nil {
+		return nil, err
+	}
+	root := tree.Root()
+	tool.logger.Info().Int("leaves", len(leaves)).Int("depth", tree.Depth()).Str("merkle_root", hexutil.Encode(root)).Msg("merkle tree built")
+
+	for i, proof := range proofs {
+		proof.Proof, err = tree.Proof(leaves[i])
+		if err != nil {
+			return nil, err
+		}
+		if !util.VerifyMerkleProof(root, proof.Proof, leaves[i]) {
+			return nil, fmt.Errorf("%w: %s %s", approval.ErrInvalidMerkleProof, proof.Address, proof.Coin.Denom)
+		}
+	}
+	if err := writeJSON(outputPath, proofs); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func leafOf(address types.AccAddress, coin types.Coin) ([]byte, error) {
+	return (&store.Proof{Address: address, Denom: coin.Denom, Amount: coin.Amount}).Serialize()
+}
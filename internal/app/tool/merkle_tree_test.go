@@ -0,0 +1,58 @@
+package tool
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+
+	"github.com/bnb-chain/token-recover-approver/internal/store/memory"
+)
+
+func TestBuildMerkleTree_Duplicates(t *testing.T) {
+	coin := func(denom string, amount int64) types.Coin { return types.Coin{Denom: denom, Amount: amount} }
+	tests := []struct {
+		name     string
+		accounts []memory.Account
+		wantErr  bool
+	}{
+		{"distinct", []memory.Account{
+			{Address: []byte{1}, Coins: types.Coins{coin("ABC-123", 7), coin("BNB", 50)}},
+			{Address: []byte{2}, Coins: types.Coins{coin("BNB", 100)}},
+		}, false},
+		{"duplicate denom", []memory.Account{
+			{Address: []byte{1}, Coins: types.Coins{coin("BNB", 50), coin("BNB", 7)}},
+		}, true},
+		{"duplicate account", []memory.Account{
+			{Address: []byte{1}, Coins: types.Coins{coin("BNB", 50)}},
+			{Address: []byte{2}, Coins: types.Coins{coin("BNB", 100)}},
+			{Address: []byte{1}, Coins: types.Coins{coin("BNB", 50)}},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			data, err := json.Marshal(tt.accounts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			accountsPath := filepath.Join(dir, "accounts.json")
+			if err := os.WriteFile(accountsPath, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			tool := &Tool{logger: &zerolog.Logger{}}
+			_, err = tool.BuildMerkleTree(accountsPath, filepath.Join(dir, "proofs.json"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildMerkleTree() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "duplicate coin BNB") {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package tool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bnb-chain/token-recover-approver/internal/injection"
+	"github.com/bnb-chain/token-recover-approver/internal/module/approval"
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+	"github.com/bnb-chain/token-recover-approver/internal/store/memory"
+)
+
+// stdio is the path which reads stdin or writes stdout
+const stdio = "-"
+
+var ErrApprovalVerification = errors.New("approval verification failed")
+
+// SignedApproval is the output of sign-request and the input of verify-approval.
+type SignedApproval struct {
+	Request  *approval.GetTokenRecoverApprovalRequest  `json:"request"`
+	Approval *approval.GetTokenRecoverApprovalResponse `json:"approval"`
+}
+
+// SignRequest runs the approval pipeline for the request with the configured key,
+// the proofs are read from proofsPath if set, or from the configured store otherwise.
+// The ledger of a memory store only lives in this run, the approval policy then doesn't see the approvals
+// issued elsewhere.
+func (tool *Tool) SignRequest(requestPath, proofsPath, outputPath string) error {
+	req := &approval.GetTokenRecoverApprovalRequest{}
+	if err := readJSON(requestPath, req); err != nil {
+		return err
+	}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	var (
+		proofStore    store.Store
+		approvalStore store.ApprovalStore
+	)
+	if proofsPath != "" {
+		memoryStore, err := memory.NewMemoryStore(proofsPath)
+		if err != nil {
+			return err
+		}
+		proofStore, approvalStore = memoryStore, memoryStore
+		tool.logger.Warn().Str("policy", tool.config.Approval.Policy).Msg("the approval ledger is kept in memory, approvals issued by the service are not checked")
+	} else {
+		var err error
+		approvalStore, err = injection.InitApprovalStore(tool.config, tool.store)
+		if err != nil {
+			return err
+		}
+		proofStore = tool.store
+	}
+	// keys are only loaded by the tools which sign
+	signer, err := injection.InitThresholdSigner(tool.config, tool.logger)
+	if err != nil {
+		return err
+	}
+	svc, err := approval.NewApprovalService(tool.config, signer, proofStore, approvalStore, injection.InitMetrics(injection.InitPrometheusRegister()), tool.logger)
+	if err != nil {
+		return err
+	}
+
+	resp, err := svc.GetTokenRecoverApproval(req)
+	if err != nil {
+		return err
+	}
+	return writeJSON(outputPath, &SignedApproval{Request: req, Approval: resp})
+}
+
+// VerifyApproval recomputes the digest of the signed approval and recovers its signers, at least the configured
+// threshold of approvers must have signed and no one else. The approvers default to the configured signers.
+// An approval past its deadline fails the verification.
+func (tool *Tool) VerifyApproval(approvalPath string, approvers []common.Address) ([]common.Address, error) {
+	signed := &SignedApproval{}
+	if err := readJSON(approvalPath, signed); err != nil {
+		return nil, err
+	}
+	if signed.Request == nil || signed.Approval == nil {
+		return nil, fmt.Errorf("%w: request or approval is missing", ErrApprovalVerification)
+	}
+
+	signers, err := approval.RecoverApprovalSigners(tool.config, signed.Request, signed.Approval)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrApprovalVerification, err)
+	}
+	if deadline := signed.Approval.Deadline; deadline != 0 && deadline <= uint64(time.Now().Unix()) {
+		return nil, fmt.Errorf("%w: approval expired at %s", ErrApprovalVerification, time.Unix(int64(deadline), 0).UTC().Format(time.RFC3339))
+	}
+	if len(approvers) == 0 {
+		signer, err := injection.InitThresholdSigner(tool.config, tool.logger)
+		if err != nil {
+			return nil, fmt.Errorf("no approvers given and the configured signers can not be loaded: %w", err)
+		}
+		approvers = signer.Addresses()
+	}
+
+	allowed := make(map[common.Address]struct{}, len(approvers))
+	for _, approver := range approvers {
+		allowed[approver] = struct{}{}
+	}
+	signedApprovers := make(map[common.Address]struct{}, len(signers))
+	for _, signer := range signers {
+		if _, ok := allowed[signer]; !ok {
+			return nil, fmt.Errorf("%w: %s is not an approver", ErrApprovalVerification, signer)
+		}
+		signedApprovers[signer] = struct{}{}
+	}
+	if len(signedApprovers) < tool.config.Secret.Threshold {
+		return nil, fmt.Errorf("%w: %d approvers signed, threshold is %d", ErrApprovalVerification, len(signedApprovers), tool.config.Secret.Threshold)
+	}
+	return signers, nil
+}
+
+func readJSON(path string, v any) error {
+	var r io.Reader = os.Stdin
+	if path != stdio && path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+	return json.NewDecoder(r).Decode(v)
+}
+
+func writeJSON(path string, v any) error {
+	if path == stdio || path == "" {
+		return encodeJSON(os.Stdout, v)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := encodeJSON(file, v); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func encodeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,105 @@
+package tool
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/hd"
+	"github.com/cosmos/cosmos-sdk/types"
+	bip39 "github.com/cosmos/go-bip39"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"github.com/bnb-chain/token-recover-approver/internal/module/approval"
+)
+
+var ErrMissingOwnerKey = errors.New("mnemonic or private key is required")
+
+// OwnerKey is the BC key of the token owner, given as a mnemonic or a hex private key.
+type OwnerKey struct {
+	Mnemonic   string
+	PrivateKey string
+	// Account and Index select the HD path m/44'/714'/account'/0/index of the mnemonic.
+	Account uint32
+	Index   uint32
+}
+
+// ReadOwnerKey reads a mnemonic or a hex private key from the first line of stdin,
+// so that the owner key doesn't have to be passed as a flag.
+func ReadOwnerKey() (string, error) {
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read owner key from stdin: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// OwnerSign signs the token recover request of the owner key with the amount in the configured store,
+// and returns the approval request which can be posted to the approver.
+func (tool *Tool) OwnerSign(key *OwnerKey, tokenSymbol string, claimAddress common.Address) (*approval.GetTokenRecoverApprovalRequest, error) {
+	privKey, err := key.privKey()
+	if err != nil {
+		return nil, err
+	}
+	pubKey := privKey.PubKey().(secp256k1.PubKeySecp256k1)
+	ownerAddr := types.AccAddress(pubKey.Address())
+	tool.logger.Info().Str("address", ownerAddr.String()).Str("symbol", tokenSymbol).Msg("owner sign")
+
+	proof, err := tool.store.GetAccountAssetProof(ownerAddr, tokenSymbol)
+	if err != nil {
+		return nil, err
+	}
+	if proof.Amount == 0 {
+		return nil, approval.ErrZeroAmount
+	}
+	msgBytes, err := approval.TokenRecoverRequestMsgBytes(tool.config.ChainID, tokenSymbol, proof.Amount, claimAddress)
+	if err != nil {
+		return nil, err
+	}
+	tool.logger.Debug().Str("msg", string(msgBytes)).Msg("GetStdMsgBytes")
+	signature, err := privKey.Sign(msgBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &approval.GetTokenRecoverApprovalRequest{
+		TokenSymbol:    tokenSymbol,
+		OwnerPubKey:    hexutil.Encode(pubKey[:]),
+		OwnerSignature: hexutil.Encode(signature),
+		ClaimAddress:   claimAddress,
+	}, nil
+}
+
+func (key *OwnerKey) privKey() (secp256k1.PrivKeySecp256k1, error) {
+	var privKey secp256k1.PrivKeySecp256k1
+	switch {
+	case key.PrivateKey != "":
+		privKeyBytes, err := hex.DecodeString(strings.TrimPrefix(key.PrivateKey, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("decode private key: %w", err)
+		}
+		if len(privKeyBytes) != 32 {
+			return nil, fmt.Errorf("invalid private key length %d", len(privKeyBytes))
+		}
+		privKey = privKeyBytes
+	case key.Mnemonic != "":
+		seed, err := bip39.NewSeedWithErrorChecking(key.Mnemonic, "")
+		if err != nil {
+			return nil, err
+		}
+		master, chainCode := hd.ComputeMastersFromSeed(seed)
+		derived, err := hd.DerivePrivateKeyForPath(master, chainCode, hd.NewFundraiserParams(key.Account, key.Index).String())
+		if err != nil {
+			return nil, err
+		}
+		privKey = derived[:]
+	default:
+		return nil, ErrMissingOwnerKey
+	}
+	return privKey, nil
+}
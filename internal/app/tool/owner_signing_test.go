@@ -0,0 +1,162 @@
+package tool
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"github.com/bnb-chain/token-recover-approver/internal/config"
+	"github.com/bnb-chain/token-recover-approver/internal/injection"
+	collector "github.com/bnb-chain/token-recover-approver/internal/metrics/prometheus"
+	"github.com/bnb-chain/token-recover-approver/internal/module/approval"
+	"github.com/bnb-chain/token-recover-approver/internal/store/memory"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/local"
+)
+
+const (
+	testChainID  = "Binance-Chain-Ganges"
+	testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+)
+
+func ownerAddress(t *testing.T, key *OwnerKey) types.AccAddress {
+	t.Helper()
+	privKey, err := key.privKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return types.AccAddress(privKey.PubKey().(secp256k1.PubKeySecp256k1).Address())
+}
+
+func TestOwnerKey_PrivKey(t *testing.T) {
+	injection.InitSDK(&config.Config{ChainID: testChainID}, &zerolog.Logger{})
+	tests := []struct {
+		name        string
+		key         *OwnerKey
+		wantPrivKey string
+		wantAddress string
+	}{
+		{"mnemonic", &OwnerKey{Mnemonic: testMnemonic},
+			"3955f430d8372b601f3a70c10a707f94c509fb3c51c1e94ddbb7ab9906cb659d", "tbnb1rxhz5vdv4fvdjye8gxqvfv0yvg20jtlw8qq48u"},
+		{"mnemonic account and index", &OwnerKey{Mnemonic: testMnemonic, Account: 1, Index: 2},
+			"145bc744492ae609dc69837ea6aa46234cea6c381ef27cd318dc52197a93a7f0", "tbnb1rvth4lle7n0hufzq7zgpgn3mnqc59cnfzdgcqu"},
+		{"private key", &OwnerKey{PrivateKey: "3955f430d8372b601f3a70c10a707f94c509fb3c51c1e94ddbb7ab9906cb659d"},
+			"3955f430d8372b601f3a70c10a707f94c509fb3c51c1e94ddbb7ab9906cb659d", "tbnb1rxhz5vdv4fvdjye8gxqvfv0yvg20jtlw8qq48u"},
+		{"0x private key", &OwnerKey{PrivateKey: "0x145bc744492ae609dc69837ea6aa46234cea6c381ef27cd318dc52197a93a7f0"},
+			"145bc744492ae609dc69837ea6aa46234cea6c381ef27cd318dc52197a93a7f0", "tbnb1rvth4lle7n0hufzq7zgpgn3mnqc59cnfzdgcqu"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privKey, err := tt.key.privKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := common.Bytes2Hex(privKey); got != tt.wantPrivKey {
+				t.Errorf("privKey() = %s, want %s", got, tt.wantPrivKey)
+			}
+			if got := ownerAddress(t, tt.key).String(); got != tt.wantAddress {
+				t.Errorf("address = %s, want %s", got, tt.wantAddress)
+			}
+		})
+	}
+
+	for _, key := range []*OwnerKey{
+		{PrivateKey: "0x3955f430d8372b601f3a70c10a707f94c509fb3c51c1e94ddbb7ab9906cb65"},
+		{PrivateKey: "not hex"},
+		{Mnemonic: "abandon abandon abandon"},
+	} {
+		if _, err := key.privKey(); err == nil {
+			t.Errorf("privKey() of %+v expects an error", key)
+		}
+	}
+	if _, err := (&OwnerKey{}).privKey(); !errors.Is(err, ErrMissingOwnerKey) {
+		t.Errorf("privKey() without key error = %v, want ErrMissingOwnerKey", err)
+	}
+}
+
+func TestOwnerSign(t *testing.T) {
+	injection.InitSDK(&config.Config{ChainID: testChainID}, &zerolog.Logger{})
+	key := &OwnerKey{Mnemonic: testMnemonic, Account: 1, Index: 2}
+	owner := ownerAddress(t, key)
+	claimAddress := common.HexToAddress("0x2e9247B67ae885a8dcfBf77Eb6d0e93A32bea24C")
+
+	// a snapshot holding the owner assets and another account
+	dir := t.TempDir()
+	data, err := json.Marshal([]memory.Account{
+		{Address: owner, Coins: types.Coins{{Denom: "BNB", Amount: 100}, {Denom: "XYZ-456", Amount: 0}}},
+		{Address: ownerAddress(t, &OwnerKey{Mnemonic: testMnemonic}), Coins: types.Coins{{Denom: "BNB", Amount: 50}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountsPath, proofsPath := filepath.Join(dir, "accounts.json"), filepath.Join(dir, "proofs.json")
+	if err := os.WriteFile(accountsPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tool := &Tool{logger: &zerolog.Logger{}, config: &config.Config{ChainID: testChainID}}
+	root, err := tool.BuildMerkleTree(accountsPath, proofsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tool.config.MerkleRoot = hexutil.Encode(root)
+	proofStore, err := memory.NewMemoryStore(proofsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tool.store = proofStore
+
+	req, err := tool.OwnerSign(key, "BNB", claimAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tool.OwnerSign(key, "XYZ-456", claimAddress); err == nil {
+		t.Error("OwnerSign() of an asset without proof expects an error")
+	}
+
+	// the request body is accepted by the approver
+	body, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	posted := &approval.GetTokenRecoverApprovalRequest{}
+	if err := json.Unmarshal(body, posted); err != nil {
+		t.Fatal(err)
+	}
+	if err := posted.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	km, err := local.NewLocalKeyManager("afc2986f283cf5f9d17e04c6a12ccf8fa46149fc37d48e11abef15a46ae34eb7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := keymanager.NewThresholdSigner(1, km)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, err := approval.NewApprovalService(tool.config, signer, proofStore, proofStore, collector.NewCollector(prometheus.NewRegistry()), tool.logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := svc.GetTokenRecoverApproval(posted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Amount.Int64() != 100 {
+		t.Errorf("approved amount %s, want 100", resp.Amount)
+	}
+
+	// the signature is bound to the claim address
+	posted.ClaimAddress = common.HexToAddress("0x0000000000000000000000000000000000000001")
+	if _, err := svc.GetTokenRecoverApproval(posted); !errors.Is(err, approval.ErrInvalidOwnerSignature) {
+		t.Errorf("GetTokenRecoverApproval() of another claim address error = %v, want ErrInvalidOwnerSignature", err)
+	}
+}
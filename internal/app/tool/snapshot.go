@@ -0,0 +1,187 @@
+package tool
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bnb-chain/node/app/config"
+	nodetypes "github.com/bnb-chain/node/common/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/token-recover-approver/internal/store/memory"
+)
+
+// DenomTotal is the total amount and the number of holders of a denom in the snapshot.
+type DenomTotal struct {
+	Denom   string   `json:"denom"`
+	Amount  *big.Int `json:"amount"`
+	Holders int64    `json:"holders"`
+}
+
+// ExportSnapshot writes every non-escrow account of the fullnode with its free, frozen and locked coins summed
+// to the accounts.json format read by build-merkle-tree, and returns the totals sorted by denom.
+// The accounts are written in the order of the account store, which is sorted by address, to a temporary file
+// which replaces outputPath once the export is complete.
+func (tool *Tool) ExportSnapshot(nodeCtx *config.BNBBeaconChainContext, home, outputPath string) ([]*DenomTotal, error) {
+	dapp, appCtx, err := openApp(nodeCtx, home)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(outputPath), filepath.Base(outputPath)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	if err := file.Chmod(0o644); err != nil {
+		return nil, err
+	}
+
+	iterate := func(fn func(acc nodetypes.NamedAccount) (stop bool)) {
+		dapp.AccountKeeper.IterateAccounts(appCtx, func(acc sdk.Account) (stop bool) {
+			return fn(acc.(nodetypes.NamedAccount))
+		})
+	}
+	totals, err := tool.exportAccounts(iterate, escrowAccs(tool.logger), file)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Sync(); err != nil {
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(file.Name(), outputPath); err != nil {
+		return nil, err
+	}
+	tool.logger.Info().Int("denoms", len(totals)).Str("output", outputPath).Msg("snapshot exported")
+	return totals, nil
+}
+
+// namedAccountIterator streams the accounts of the chain in the order of the account store until fn stops it.
+type namedAccountIterator func(fn func(acc nodetypes.NamedAccount) (stop bool))
+
+// exportAccounts writes the iterated accounts which are not escrow accounts and hold coins to w,
+// with their coins sorted by denom, and returns the totals sorted by denom.
+// The accounts must be iterated sorted by address, so that the snapshot is.
+func (tool *Tool) exportAccounts(iterate namedAccountIterator, escrowAccs map[string]struct{}, w io.Writer) ([]*DenomTotal, error) {
+	writer := newJSONArrayWriter(w)
+	totals := make(map[string]*DenomTotal)
+	var (
+		count    int64
+		lastAddr sdk.AccAddress
+		iterErr  error
+	)
+	iterate(func(acc nodetypes.NamedAccount) (stop bool) {
+		addr := acc.GetAddress()
+		if _, matched := escrowAccs[addr.String()]; matched {
+			tool.logger.Info().Msg("skip escrow account: " + addr.String())
+			return false
+		}
+		if lastAddr != nil && bytes.Compare(lastAddr, addr) >= 0 {
+			iterErr = fmt.Errorf("accounts are not sorted: %s after %s", addr, lastAddr)
+			return true
+		}
+		lastAddr = addr
+
+		coins := make(sdk.Coins, 0)
+		for _, coin := range accountCoins(acc).Sort() {
+			if coin.Amount <= 0 {
+				continue
+			}
+			coins = append(coins, coin)
+			total, ok := totals[coin.Denom]
+			if !ok {
+				total = &DenomTotal{Denom: coin.Denom, Amount: new(big.Int)}
+				totals[coin.Denom] = total
+			}
+			total.Amount.Add(total.Amount, big.NewInt(coin.Amount))
+			total.Holders++
+		}
+		if len(coins) == 0 {
+			return false
+		}
+
+		iterErr = writer.Write(&memory.Account{
+			Address:       addr,
+			AccountNumber: acc.GetAccountNumber(),
+			Coins:         coins,
+		})
+		if iterErr != nil {
+			return true
+		}
+		count++
+		if count%100000 == 0 {
+			tool.logger.Info().Int64("count", count).Msg("exporting accounts")
+		}
+		return false
+	})
+	if iterErr != nil {
+		return nil, iterErr
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	tool.logger.Info().Int64("accounts", count).Msg("accounts exported")
+	return sortDenomTotals(totals), nil
+}
+
+func sortDenomTotals(totals map[string]*DenomTotal) []*DenomTotal {
+	sorted := make([]*DenomTotal, 0, len(totals))
+	for _, total := range totals {
+		sorted = append(sorted, total)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Denom < sorted[j].Denom
+	})
+	return sorted
+}
+
+// jsonArrayWriter streams the elements of a JSON array, which can be read by util.NewJSONStream.
+type jsonArrayWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func newJSONArrayWriter(w io.Writer) *jsonArrayWriter {
+	return &jsonArrayWriter{w: bufio.NewWriter(w)}
+}
+
+func (jw *jsonArrayWriter) Write(v any) error {
+	data, err := json.MarshalIndent(v, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	delimiter := "[\n  "
+	if jw.count > 0 {
+		delimiter = ",\n  "
+	}
+	if _, err := jw.w.WriteString(delimiter); err != nil {
+		return err
+	}
+	if _, err := jw.w.Write(data); err != nil {
+		return err
+	}
+	jw.count++
+	return nil
+}
+
+func (jw *jsonArrayWriter) Close() error {
+	end := "\n]\n"
+	if jw.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := jw.w.WriteString(end); err != nil {
+		return err
+	}
+	return jw.w.Flush()
+}
@@ -0,0 +1,139 @@
+package tool
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	nodetypes "github.com/bnb-chain/node/common/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/rs/zerolog"
+
+	"github.com/bnb-chain/token-recover-approver/internal/store/memory"
+	"github.com/bnb-chain/token-recover-approver/pkg/util"
+)
+
+func TestJSONArrayWriter(t *testing.T) {
+	type element struct {
+		Name  string `json:"name"`
+		Value int    `json:"value"`
+	}
+	tests := []struct {
+		name     string
+		elements []*element
+	}{
+		{"empty", nil},
+		{"single", []*element{{"a", 1}}},
+		{"multiple", []*element{{"a", 1}, {"b", 2}, {"c", 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "elements.json")
+			file, err := os.Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			writer := newJSONArrayWriter(file)
+			for _, e := range tt.elements {
+				if err := writer.Write(e); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if err := file.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			stream := util.NewJSONStream(func() any {
+				return &element{}
+			})
+			go stream.Start(path)
+			var got []*element
+			for data := range stream.Watch() {
+				if data.Error != nil {
+					t.Fatal(data.Error)
+				}
+				got = append(got, data.Data.(*element))
+			}
+			if !reflect.DeepEqual(got, tt.elements) {
+				t.Errorf("read %+v, want %+v", got, tt.elements)
+			}
+		})
+	}
+}
+
+// makeNamedAccountIterator iterates app accounts in the given order.
+func makeNamedAccountIterator(accs ...*nodetypes.AppAccount) namedAccountIterator {
+	return func(fn func(acc nodetypes.NamedAccount) bool) {
+		for _, acc := range accs {
+			if fn(acc) {
+				return
+			}
+		}
+	}
+}
+
+func TestExportAccounts(t *testing.T) {
+	coin := func(denom string, amount int64) sdk.Coin { return sdk.Coin{Denom: denom, Amount: amount} }
+	addr := func(b byte) sdk.AccAddress { return bytes.Repeat([]byte{b}, sdk.AddrLen) }
+	account := func(address sdk.AccAddress, number int64, coins, frozen, locked sdk.Coins) *nodetypes.AppAccount {
+		return &nodetypes.AppAccount{
+			BaseAccount: auth.BaseAccount{Address: address, Coins: coins, AccountNumber: number},
+			FrozenCoins: frozen,
+			LockedCoins: locked,
+		}
+	}
+	escrow := addr(2)
+	iterate := makeNamedAccountIterator(
+		account(addr(1), 10, sdk.Coins{coin("BNB", 10), coin("XYZ-456", 5)}, sdk.Coins{coin("BNB", 2)}, sdk.Coins{coin("ABC-123", 3), coin("BNB", 1)}),
+		account(escrow, 11, sdk.Coins{coin("BNB", 1000)}, nil, nil),
+		account(addr(3), 12, sdk.Coins{coin("BNB", 0)}, nil, nil),
+		account(addr(4), 13, nil, sdk.Coins{coin("BNB", 7)}, nil),
+	)
+
+	tool := &Tool{logger: &zerolog.Logger{}}
+	var out bytes.Buffer
+	totals, err := tool.exportAccounts(iterate, map[string]struct{}{escrow.String(): {}}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the escrow account and the account without coins are skipped, the coins are summed and sorted by denom
+	var accounts []memory.Account
+	if err := json.Unmarshal(out.Bytes(), &accounts); err != nil {
+		t.Fatal(err)
+	}
+	wantAccounts := []memory.Account{
+		{Address: addr(1), AccountNumber: 10, Coins: sdk.Coins{coin("ABC-123", 3), coin("BNB", 13), coin("XYZ-456", 5)}},
+		{Address: addr(4), AccountNumber: 13, Coins: sdk.Coins{coin("BNB", 7)}},
+	}
+	if !reflect.DeepEqual(accounts, wantAccounts) {
+		t.Fatalf("exported %+v, want %+v", accounts, wantAccounts)
+	}
+	wantTotals := []*DenomTotal{
+		{Denom: "ABC-123", Amount: big.NewInt(3), Holders: 1},
+		{Denom: "BNB", Amount: big.NewInt(20), Holders: 2},
+		{Denom: "XYZ-456", Amount: big.NewInt(5), Holders: 1},
+	}
+	if !reflect.DeepEqual(totals, wantTotals) {
+		t.Fatalf("totals %+v, want %+v", totals, wantTotals)
+	}
+
+	// the snapshot must be sorted by address
+	unsorted := makeNamedAccountIterator(
+		account(addr(4), 13, sdk.Coins{coin("BNB", 7)}, nil, nil),
+		account(addr(1), 10, sdk.Coins{coin("BNB", 10)}, nil, nil),
+	)
+	if _, err := tool.exportAccounts(unsorted, nil, io.Discard); err == nil || !strings.Contains(err.Error(), "not sorted") {
+		t.Fatalf("expected an error for unsorted accounts, got %v", err)
+	}
+}
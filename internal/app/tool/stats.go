@@ -0,0 +1,178 @@
+package tool
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+)
+
+// Holder is an account holding a denom.
+type Holder struct {
+	Address string `json:"address"`
+	Amount  int64  `json:"amount"`
+
+	addr sdk.AccAddress
+}
+
+// DenomStats is the recoverable supply of a denom, TopHolders are sorted by amount descending.
+type DenomStats struct {
+	Denom      string    `json:"denom"`
+	Holders    int64     `json:"holders"`
+	Amount     *big.Int  `json:"amount"`
+	TopHolders []*Holder `json:"top_holders"`
+
+	top holderHeap
+}
+
+// DepthCount is the number of proofs of a depth.
+type DepthCount struct {
+	Depth  int   `json:"depth"`
+	Proofs int64 `json:"proofs"`
+}
+
+// SnapshotStats is the recoverable supply of a snapshot, the denoms and depths are sorted ascending.
+type SnapshotStats struct {
+	Proofs   int64         `json:"proofs"`
+	Accounts int64         `json:"accounts"`
+	Denoms   []*DenomStats `json:"denoms"`
+	Depths   []*DepthCount `json:"depths"`
+}
+
+// Stats streams the proofs file, or the configured store if the path is empty,
+// and sums the holders and the amount of every denom with its top largest holders.
+func (tool *Tool) Stats(proofPath string, top int) (*SnapshotStats, error) {
+	var source proofSource = tool.store
+	if proofPath != "" {
+		source = proofFile(proofPath)
+	}
+	return tool.stats(source, top)
+}
+
+func (tool *Tool) stats(source proofSource, top int) (*SnapshotStats, error) {
+	stats := &SnapshotStats{}
+	accounts := make(map[string]struct{})
+	denoms := make(map[string]*DenomStats)
+	depths := make(map[int]int64)
+	err := source.Iterate(func(proof *store.Proof) error {
+		stats.Proofs++
+		accounts[string(proof.Address)] = struct{}{}
+		depths[len(proof.Proof)]++
+
+		denom, ok := denoms[proof.Denom]
+		if !ok {
+			denom = &DenomStats{Denom: proof.Denom, Amount: new(big.Int)}
+			denoms[proof.Denom] = denom
+		}
+		denom.Holders++
+		denom.Amount.Add(denom.Amount, big.NewInt(proof.Amount))
+		denom.top.push(&Holder{addr: proof.Address, Amount: proof.Amount}, top)
+
+		if stats.Proofs%100000 == 0 {
+			tool.logger.Info().Int64("count", stats.Proofs).Msg("reading proofs")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	stats.Accounts = int64(len(accounts))
+
+	stats.Denoms = make([]*DenomStats, 0, len(denoms))
+	for _, denom := range denoms {
+		denom.TopHolders = denom.top.sorted()
+		stats.Denoms = append(stats.Denoms, denom)
+	}
+	sort.Slice(stats.Denoms, func(i, j int) bool {
+		return stats.Denoms[i].Denom < stats.Denoms[j].Denom
+	})
+	stats.Depths = make([]*DepthCount, 0, len(depths))
+	for depth, proofs := range depths {
+		stats.Depths = append(stats.Depths, &DepthCount{Depth: depth, Proofs: proofs})
+	}
+	sort.Slice(stats.Depths, func(i, j int) bool {
+		return stats.Depths[i].Depth < stats.Depths[j].Depth
+	})
+	return stats, nil
+}
+
+// Write writes the stats in the format.
+func (stats *SnapshotStats) Write(w io.Writer, format string) error {
+	switch format {
+	case TextOutputFormat:
+		stats.Print(w)
+		return nil
+	case JSONOutputFormat:
+		return encodeJSON(w, stats)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// Print writes the stats as text, every denom with its top holders and then the depth distribution.
+func (stats *SnapshotStats) Print(w io.Writer) {
+	fmt.Fprintf(w, "proofs: %d, accounts: %d, denoms: %d\n", stats.Proofs, stats.Accounts, len(stats.Denoms))
+	for _, denom := range stats.Denoms {
+		fmt.Fprintf(w, "%s\tholders %d\tamount %s\n", denom.Denom, denom.Holders, denom.Amount)
+		for i, holder := range denom.TopHolders {
+			fmt.Fprintf(w, "\t%d\t%s\t%d\n", i+1, holder.Address, holder.Amount)
+		}
+	}
+	for _, depth := range stats.Depths {
+		fmt.Fprintf(w, "depth %d\tproofs %d\n", depth.Depth, depth.Proofs)
+	}
+}
+
+// holderHeap keeps the largest holders, the smallest of them is on top to be replaced.
+type holderHeap []*Holder
+
+// less orders the holders by amount, the larger address is the smaller holder so that ties are deterministic.
+func (h holderHeap) less(a, b *Holder) bool {
+	if a.Amount != b.Amount {
+		return a.Amount < b.Amount
+	}
+	return bytes.Compare(a.addr, b.addr) > 0
+}
+
+func (h holderHeap) Len() int           { return len(h) }
+func (h holderHeap) Less(i, j int) bool { return h.less(h[i], h[j]) }
+func (h holderHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *holderHeap) Push(x any)        { *h = append(*h, x.(*Holder)) }
+func (h *holderHeap) Pop() any {
+	old := *h
+	holder := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return holder
+}
+
+// push adds the holder if it is one of the limit largest.
+func (h *holderHeap) push(holder *Holder, limit int) {
+	if limit <= 0 {
+		return
+	}
+	if h.Len() < limit {
+		heap.Push(h, holder)
+		return
+	}
+	if h.less((*h)[0], holder) {
+		(*h)[0] = holder
+		heap.Fix(h, 0)
+	}
+}
+
+// sorted returns the holders by amount descending, the heap is emptied.
+func (h *holderHeap) sorted() []*Holder {
+	holders := make([]*Holder, h.Len())
+	for i := len(holders) - 1; i >= 0; i-- {
+		holder := heap.Pop(h).(*Holder)
+		holder.Address = holder.addr.String()
+		holders[i] = holder
+	}
+	return holders
+}
@@ -0,0 +1,54 @@
+package tool
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestHolderHeap(t *testing.T) {
+	amounts := []int64{5, 1, 9, 3, 9, 7, 2, 8}
+	var h holderHeap
+	for i, amount := range amounts {
+		h.push(&Holder{addr: []byte{byte(i)}, Amount: amount}, 3)
+	}
+	holders := h.sorted()
+	want := []struct {
+		addr   byte
+		amount int64
+	}{{2, 9}, {4, 9}, {7, 8}}
+	if len(holders) != len(want) {
+		t.Fatalf("got %d holders, want %d", len(holders), len(want))
+	}
+	for i, holder := range holders {
+		if holder.addr[0] != want[i].addr || holder.Amount != want[i].amount {
+			t.Errorf("holder %d is %x with %d, want %x with %d", i, holder.addr, holder.Amount, want[i].addr, want[i].amount)
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	proofs := proofList{
+		{Address: []byte{1}, Denom: "BNB", Amount: 50, Proof: make([][]byte, 2)},
+		{Address: []byte{1}, Denom: "ABC-123", Amount: 7, Proof: make([][]byte, 2)},
+		{Address: []byte{2}, Denom: "BNB", Amount: 100, Proof: make([][]byte, 3)},
+	}
+	tool := &Tool{logger: &zerolog.Logger{}}
+	stats, err := tool.stats(proofs, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Proofs != 3 || stats.Accounts != 2 {
+		t.Fatalf("got %d proofs of %d accounts, want 3 of 2", stats.Proofs, stats.Accounts)
+	}
+	if len(stats.Denoms) != 2 || stats.Denoms[0].Denom != "ABC-123" {
+		t.Fatalf("unexpected denoms %+v", stats.Denoms)
+	}
+	bnb := stats.Denoms[1]
+	if bnb.Holders != 2 || bnb.Amount.Int64() != 150 || len(bnb.TopHolders) != 1 || bnb.TopHolders[0].Amount != 100 {
+		t.Errorf("unexpected BNB stats %+v", bnb)
+	}
+	if len(stats.Depths) != 2 || *stats.Depths[0] != (DepthCount{Depth: 2, Proofs: 2}) || *stats.Depths[1] != (DepthCount{Depth: 3, Proofs: 1}) {
+		t.Errorf("unexpected depths %+v %+v", stats.Depths[0], stats.Depths[1])
+	}
+}
@@ -0,0 +1,118 @@
+package tool
+
+import (
+	"context"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// pipelineDepth is the number of accounts in flight per worker
+const pipelineDepth = 64
+
+// accountJob is an account to verify, seq is its position in the account iteration.
+type accountJob struct {
+	seq   int64
+	addr  sdk.AccAddress
+	coins sdk.Coins
+}
+
+// coinResult is the verification of a coin, the coins after a failed verification are not verified.
+type coinResult struct {
+	coin        sdk.Coin
+	discrepancy *Discrepancy
+	err         error
+}
+
+type accountResult struct {
+	seq   int64
+	addr  sdk.AccAddress
+	coins []coinResult
+}
+
+// accountIterator calls fn with every account to verify until fn returns false.
+type accountIterator func(fn func(addr sdk.AccAddress, coins sdk.Coins) bool)
+
+// verifyAccounts verifies the coins of the iterated accounts with a pool of workers.
+// The results are handled in the order of the iteration, so they are the same as a sequential verification,
+// handle returns false to cancel the verification, no result is handled after.
+func (tool *Tool) verifyAccounts(iterate accountIterator, workers int, verify func(addr sdk.AccAddress, coin sdk.Coin) (*Discrepancy, error), handle func(res *accountResult) bool) {
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// slots bounds the accounts between the iteration and the ordered handling
+	slots := make(chan struct{}, workers*pipelineDepth)
+	jobs := make(chan *accountJob, workers)
+	results := make(chan *accountResult, workers)
+
+	go func() {
+		defer close(jobs)
+		var seq int64
+		iterate(func(addr sdk.AccAddress, coins sdk.Coins) bool {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return false
+			}
+			select {
+			case jobs <- &accountJob{seq: seq, addr: addr, coins: coins}:
+			case <-ctx.Done():
+				return false
+			}
+			seq++
+			return true
+		})
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				res := &accountResult{seq: job.seq, addr: job.addr, coins: make([]coinResult, 0, len(job.coins))}
+				for _, coin := range job.coins {
+					discrepancy, err := verify(job.addr, coin)
+					res.coins = append(res.coins, coinResult{coin: coin, discrepancy: discrepancy, err: err})
+					if err != nil {
+						break
+					}
+				}
+				results <- res
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// the results are drained after cancel, so that the workers are not blocked
+	pending := make(map[int64]*accountResult)
+	var next int64
+	for res := range results {
+		if ctx.Err() != nil {
+			continue
+		}
+		pending[res.seq] = res
+		for {
+			res, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			<-slots
+			if !handle(res) {
+				cancel()
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package tool
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/rs/zerolog"
+)
+
+func makeAccountIterator(n int) accountIterator {
+	return func(fn func(addr sdk.AccAddress, coins sdk.Coins) bool) {
+		for i := 0; i < n; i++ {
+			addr := sdk.AccAddress(fmt.Sprintf("address-%06d", i))
+			coins := sdk.Coins{{Denom: "ABC-123", Amount: int64(i + 1)}, {Denom: "BNB", Amount: int64(i)}}
+			if !fn(addr, coins) {
+				return
+			}
+		}
+	}
+}
+
+// verifySlowly reports a discrepancy for every seventh amount, some coins are delayed so that results arrive out of order
+func verifySlowly(addr sdk.AccAddress, coin sdk.Coin) (*Discrepancy, error) {
+	if coin.Amount%5 == 0 {
+		time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+	}
+	if coin.Amount%7 == 0 {
+		return &Discrepancy{Type: AmountMismatch, Address: string(addr), Denom: coin.Denom, ChainAmount: coin.Amount}, nil
+	}
+	return nil, nil
+}
+
+func collectDiscrepancies(workers, n int, stopAfter int) ([]string, []*Discrepancy) {
+	tool := &Tool{logger: &zerolog.Logger{}}
+	handled := make([]string, 0, n)
+	discrepancies := make([]*Discrepancy, 0)
+	tool.verifyAccounts(makeAccountIterator(n), workers, verifySlowly, func(res *accountResult) bool {
+		handled = append(handled, string(res.addr))
+		for _, coinRes := range res.coins {
+			if coinRes.discrepancy != nil {
+				discrepancies = append(discrepancies, coinRes.discrepancy)
+			}
+		}
+		return stopAfter <= 0 || len(handled) < stopAfter
+	})
+	return handled, discrepancies
+}
+
+func TestVerifyAccounts_Ordered(t *testing.T) {
+	const n = 2000
+	handled, discrepancies := collectDiscrepancies(1, n, 0)
+	if len(handled) != n {
+		t.Fatalf("handled %d accounts, want %d", len(handled), n)
+	}
+	for _, workers := range []int{2, 8, 32} {
+		parallelHandled, parallelDiscrepancies := collectDiscrepancies(workers, n, 0)
+		if !reflect.DeepEqual(handled, parallelHandled) {
+			t.Errorf("workers=%d: accounts are handled out of order", workers)
+		}
+		if !reflect.DeepEqual(discrepancies, parallelDiscrepancies) {
+			t.Errorf("workers=%d: discrepancies differ from the sequential verification", workers)
+		}
+	}
+}
+
+func TestVerifyAccounts_Cancel(t *testing.T) {
+	const stopAfter = 100
+	for _, workers := range []int{1, 8} {
+		handled, _ := collectDiscrepancies(workers, 100000, stopAfter)
+		if len(handled) != stopAfter {
+			t.Fatalf("workers=%d: handled %d accounts after cancel, want %d", workers, len(handled), stopAfter)
+		}
+		for i, addr := range handled {
+			if addr != fmt.Sprintf("address-%06d", i) {
+				t.Fatalf("workers=%d: account %d is %s", workers, i, addr)
+			}
+		}
+	}
+}
+
+func TestVerifyAccounts_Error(t *testing.T) {
+	tool := &Tool{logger: &zerolog.Logger{}}
+	storeErr := fmt.Errorf("store unavailable")
+	var handledErr error
+	tool.verifyAccounts(makeAccountIterator(1000), 8, func(addr sdk.AccAddress, coin sdk.Coin) (*Discrepancy, error) {
+		if coin.Denom == "BNB" && coin.Amount == 500 {
+			return nil, storeErr
+		}
+		return nil, nil
+	}, func(res *accountResult) bool {
+		for _, coinRes := range res.coins {
+			if coinRes.err != nil {
+				handledErr = coinRes.err
+				return false
+			}
+		}
+		return true
+	})
+	if handledErr != storeErr {
+		t.Fatalf("expected the store error, got %v", handledErr)
+	}
+}
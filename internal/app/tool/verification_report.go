@@ -0,0 +1,180 @@
+package tool
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// DiscrepancyType is the kind of mismatch between the fullnode and the store.
+type DiscrepancyType string
+
+const (
+	// MissingProof is a coin held on chain without proof in the store
+	MissingProof DiscrepancyType = "missing_proof"
+	// AmountMismatch is a coin whose amount in the store differs from the chain
+	AmountMismatch DiscrepancyType = "amount_mismatch"
+	// InvalidMerkleProof is a coin whose proof doesn't verify against the merkle root
+	InvalidMerkleProof DiscrepancyType = "invalid_merkle_proof"
+	// ExtraProof is a proof in the store without matching chain account
+	ExtraProof DiscrepancyType = "extra_proof"
+)
+
+// Reasons of the extra proofs found by the reverse pass
+const (
+	EscrowAccountReason   = "escrow account"
+	AccountNotFoundReason = "account not found"
+	DenomNotHeldReason    = "denom not held"
+)
+
+// Report formats of the verification report
+const (
+	JSONReportFormat = "json"
+	CSVReportFormat  = "csv"
+)
+
+// Discrepancy is a mismatch between the fullnode and the store.
+type Discrepancy struct {
+	Type        DiscrepancyType `json:"type"`
+	Address     string          `json:"address"`
+	Denom       string          `json:"denom"`
+	ChainAmount int64           `json:"chain_amount"`
+	StoreAmount int64           `json:"store_amount"`
+	// Reason explains the discrepancy when the type doesn't
+	Reason string `json:"reason,omitempty"`
+}
+
+func (d *Discrepancy) key() string {
+	return d.Address + ":" + d.Denom
+}
+
+// VerificationSummary counts the verified coins and the discrepancies by denom.
+type VerificationSummary struct {
+	Accounts      int64                                `json:"accounts"`
+	Verified      int64                                `json:"verified"`
+	TotalInStore  int64                                `json:"total_in_store"`
+	Discrepancies map[string]map[DiscrepancyType]int64 `json:"discrepancies"`
+}
+
+func newVerificationSummary(totalInStore int64) *VerificationSummary {
+	return &VerificationSummary{
+		TotalInStore:  totalInStore,
+		Discrepancies: make(map[string]map[DiscrepancyType]int64),
+	}
+}
+
+func (s *VerificationSummary) add(d *Discrepancy) {
+	counts, ok := s.Discrepancies[d.Denom]
+	if !ok {
+		counts = make(map[DiscrepancyType]int64)
+		s.Discrepancies[d.Denom] = counts
+	}
+	counts[d.Type]++
+}
+
+// Failed returns the number of discrepancies.
+func (s *VerificationSummary) Failed() int64 {
+	var failed int64
+	for _, counts := range s.Discrepancies {
+		for _, count := range counts {
+			failed += count
+		}
+	}
+	return failed
+}
+
+// Print writes the summary with the discrepancy counts sorted by denom.
+func (s *VerificationSummary) Print(w io.Writer) {
+	fmt.Fprintf(w, "accounts: %d, verified: %d, in store: %d, discrepancies: %d\n", s.Accounts, s.Verified, s.TotalInStore, s.Failed())
+	denoms := make([]string, 0, len(s.Discrepancies))
+	for denom := range s.Discrepancies {
+		denoms = append(denoms, denom)
+	}
+	sort.Strings(denoms)
+	for _, denom := range denoms {
+		for _, discrepancyType := range []DiscrepancyType{MissingProof, AmountMismatch, InvalidMerkleProof, ExtraProof} {
+			if count := s.Discrepancies[denom][discrepancyType]; count > 0 {
+				fmt.Fprintf(w, "%s\t%s\t%d\n", denom, discrepancyType, count)
+			}
+		}
+	}
+}
+
+// reportWriter writes every discrepancy of the verification.
+type reportWriter interface {
+	Write(d *Discrepancy) error
+	Close() error
+}
+
+// newReportWriter creates the report file in the format, an empty path writes no report.
+func newReportWriter(path, format string) (reportWriter, error) {
+	if path == "" {
+		return nopReportWriter{}, nil
+	}
+	if format != JSONReportFormat && format != CSVReportFormat {
+		return nil, fmt.Errorf("unsupported report format: %s", format)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if format == CSVReportFormat {
+		writer := csv.NewWriter(file)
+		if err := writer.Write([]string{"type", "address", "denom", "chain_amount", "store_amount", "reason"}); err != nil {
+			file.Close()
+			return nil, err
+		}
+		return &csvReportWriter{file: file, w: writer}, nil
+	}
+	return &jsonReportWriter{file: file, w: newJSONArrayWriter(file)}, nil
+}
+
+type nopReportWriter struct{}
+
+func (nopReportWriter) Write(*Discrepancy) error { return nil }
+func (nopReportWriter) Close() error             { return nil }
+
+type jsonReportWriter struct {
+	file *os.File
+	w    *jsonArrayWriter
+}
+
+func (jw *jsonReportWriter) Write(d *Discrepancy) error {
+	return jw.w.Write(d)
+}
+
+func (jw *jsonReportWriter) Close() error {
+	if err := jw.w.Close(); err != nil {
+		jw.file.Close()
+		return err
+	}
+	return jw.file.Close()
+}
+
+type csvReportWriter struct {
+	file *os.File
+	w    *csv.Writer
+}
+
+func (cw *csvReportWriter) Write(d *Discrepancy) error {
+	return cw.w.Write([]string{
+		string(d.Type),
+		d.Address,
+		d.Denom,
+		strconv.FormatInt(d.ChainAmount, 10),
+		strconv.FormatInt(d.StoreAmount, 10),
+		d.Reason,
+	})
+}
+
+func (cw *csvReportWriter) Close() error {
+	cw.w.Flush()
+	if err := cw.w.Error(); err != nil {
+		cw.file.Close()
+		return err
+	}
+	return cw.file.Close()
+}
@@ -0,0 +1,97 @@
+package tool
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var testDiscrepancies = []*Discrepancy{
+	{Type: AmountMismatch, Address: "tbnb1a", Denom: "BNB", ChainAmount: 50, StoreAmount: 40},
+	{Type: ExtraProof, Address: "tbnb1b", Denom: "ABC-123", StoreAmount: 7, Reason: AccountNotFoundReason},
+}
+
+func writeReport(t *testing.T, format string, discrepancies []*Discrepancy) []byte {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "report."+format)
+	report, err := newReportWriter(path, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range discrepancies {
+		if err := report.Write(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := report.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestJSONReportWriter(t *testing.T) {
+	var got []*Discrepancy
+	if err := json.Unmarshal(writeReport(t, JSONReportFormat, testDiscrepancies), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, testDiscrepancies) {
+		t.Fatalf("got %+v, want %+v", got, testDiscrepancies)
+	}
+
+	if data := writeReport(t, JSONReportFormat, nil); string(data) != "[]\n" {
+		t.Fatalf("expected an empty array, got %q", data)
+	}
+}
+
+func TestCSVReportWriter(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(writeReport(t, CSVReportFormat, testDiscrepancies))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"type", "address", "denom", "chain_amount", "store_amount", "reason"},
+		{"amount_mismatch", "tbnb1a", "BNB", "50", "40", ""},
+		{"extra_proof", "tbnb1b", "ABC-123", "0", "7", "account not found"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Fatalf("got %q, want %q", records, want)
+	}
+}
+
+func TestNewReportWriter(t *testing.T) {
+	if report, err := newReportWriter("", "xml"); err != nil || report != (nopReportWriter{}) {
+		t.Fatalf("expected no report without path, got %v %v", report, err)
+	}
+	if _, err := newReportWriter(filepath.Join(t.TempDir(), "report.xml"), "xml"); err == nil {
+		t.Fatal("expected error for an unsupported format")
+	}
+}
+
+func TestVerificationSummary(t *testing.T) {
+	summary := newVerificationSummary(10)
+	summary.Accounts, summary.Verified = 4, 7
+	for _, d := range append(testDiscrepancies, &Discrepancy{Type: MissingProof, Denom: "BNB"}, &Discrepancy{Type: AmountMismatch, Denom: "BNB"}) {
+		summary.add(d)
+	}
+	if failed := summary.Failed(); failed != 4 {
+		t.Fatalf("expected 4 discrepancies, got %d", failed)
+	}
+
+	var buf bytes.Buffer
+	summary.Print(&buf)
+	want := "accounts: 4, verified: 7, in store: 10, discrepancies: 4\n" +
+		"ABC-123\textra_proof\t1\n" +
+		"BNB\tmissing_proof\t1\n" +
+		"BNB\tamount_mismatch\t2\n"
+	if buf.String() != want {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+}
@@ -16,8 +16,27 @@ func Initialize(configPath string) (*Tool, error) {
 	wire.Build(
 		newTool,
 		config.NewConfig,
-		injection.InitLogger,
+		injection.InitToolLogger,
 		injection.InitStore,
 	)
 	return &Tool{}, nil
 }
+
+func InitializeWithoutMigration(configPath string) (*Tool, error) {
+	wire.Build(
+		newTool,
+		config.NewConfig,
+		injection.InitToolLogger,
+		injection.InitStoreWithoutMigration,
+	)
+	return &Tool{}, nil
+}
+
+func InitializeWithoutStore(configPath string) (*Tool, error) {
+	wire.Build(
+		newToolWithoutStore,
+		config.NewConfig,
+		injection.InitToolLogger,
+	)
+	return &Tool{}, nil
+}
@@ -18,7 +18,7 @@ func Initialize(configPath string) (*Tool, error) {
 	if err != nil {
 		return nil, err
 	}
-	logger, err := injection.InitLogger(configConfig)
+	logger, err := injection.InitToolLogger(configConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -29,3 +29,33 @@ func Initialize(configPath string) (*Tool, error) {
 	tool := newTool(logger, configConfig, store)
 	return tool, nil
 }
+
+func InitializeWithoutMigration(configPath string) (*Tool, error) {
+	configConfig, err := config.NewConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	logger, err := injection.InitToolLogger(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	store, err := injection.InitStoreWithoutMigration(configConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	tool := newTool(logger, configConfig, store)
+	return tool, nil
+}
+
+func InitializeWithoutStore(configPath string) (*Tool, error) {
+	configConfig, err := config.NewConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	logger, err := injection.InitToolLogger(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	tool := newToolWithoutStore(logger, configConfig)
+	return tool, nil
+}
@@ -19,8 +19,9 @@ func Initialize(configPath string) (Application, error) {
 		newApplication,
 		config.NewConfig,
 		injection.InitLogger,
-		injection.InitKeyManager,
+		injection.InitThresholdSigner,
 		injection.InitStore,
+		injection.InitApprovalStore,
 		injection.InitMetrics,
 		injection.InitPrometheusRegister,
 		approval.NewApprovalService,
@@ -24,7 +24,7 @@ func Initialize(configPath string) (Application, error) {
 	if err != nil {
 		return Application{}, err
 	}
-	keyManager, err := injection.InitKeyManager(configConfig)
+	thresholdSigner, err := injection.InitThresholdSigner(configConfig, logger)
 	if err != nil {
 		return Application{}, err
 	}
@@ -32,9 +32,13 @@ func Initialize(configPath string) (Application, error) {
 	if err != nil {
 		return Application{}, err
 	}
+	approvalStore, err := injection.InitApprovalStore(configConfig, store)
+	if err != nil {
+		return Application{}, err
+	}
 	registry := injection.InitPrometheusRegister()
 	metrics := injection.InitMetrics(registry)
-	approvalService, err := approval.NewApprovalService(configConfig, keyManager, store, metrics, logger)
+	approvalService, err := approval.NewApprovalService(configConfig, thresholdSigner, store, approvalStore, metrics, logger)
 	if err != nil {
 		return Application{}, err
 	}
@@ -13,14 +13,16 @@ import (
 )
 
 type Config struct {
-	ChainID          string        `mapstructure:"chain_id"`
-	MerkleRoot       string        `mapstructure:"merkle_root"`
-	Logger           LoggerConfig  `mapstructure:"logger"`
-	HTTP             HTTPConfig    `mapstructure:"http"`
-	Metrics          MetricsConfig `mapstructure:"metrics"`
-	Secret           SecretConfig  `mapstructure:"secret"`
-	Store            StoreConfig   `mapstructure:"store"`
-	AccountWhiteList []string      `mapstructure:"account_white_list"`
+	ChainID          string         `mapstructure:"chain_id"`
+	MerkleRoot       string         `mapstructure:"merkle_root"`
+	Logger           LoggerConfig   `mapstructure:"logger"`
+	HTTP             HTTPConfig     `mapstructure:"http"`
+	Metrics          MetricsConfig  `mapstructure:"metrics"`
+	Secret           SecretConfig   `mapstructure:"secret"`
+	Store            StoreConfig    `mapstructure:"store"`
+	Approval         ApprovalConfig `mapstructure:"approval"`
+	Signer           SignerConfig   `mapstructure:"signer"`
+	AccountWhiteList []string       `mapstructure:"account_white_list"`
 }
 
 func defaultConfig(v *viper.Viper) {
@@ -60,8 +62,11 @@ func defaultHTTPConfig(v *viper.Viper) {
 }
 
 type MetricsConfig struct {
-	Enable            bool          `mapstructure:"enable"`
-	PProf             bool          `mapstructure:"pprof"`
+	Enable bool `mapstructure:"enable"`
+	PProf  bool `mapstructure:"pprof"`
+	Admin  bool `mapstructure:"admin"`
+	// AdminToken is the bearer token of the admin router, without it only loopback clients are served
+	AdminToken        string        `mapstructure:"admin_token"`
 	Path              string        `mapstructure:"path"`
 	Addr              string        `mapstructure:"addr"`
 	Port              uint16        `mapstructure:"port"`
@@ -75,6 +80,8 @@ type MetricsConfig struct {
 func defaultMetricsConfig(v *viper.Viper) {
 	v.SetDefault("metrics.enable", true)
 	v.SetDefault("metrics.pprof", false)
+	v.SetDefault("metrics.admin", false)
+	v.SetDefault("metrics.admin_token", "")
 	v.SetDefault("metrics.path", "/metrics")
 	v.SetDefault("metrics.addr", "0.0.0.0")
 	v.SetDefault("metrics.port", 6060)
@@ -86,9 +93,35 @@ func defaultMetricsConfig(v *viper.Viper) {
 }
 
 type SecretConfig struct {
+	// KeyConfig is the single approver key, it is used if Secrets is empty
+	KeyConfig `mapstructure:",squash"`
+	Secrets   []KeyConfig `mapstructure:"secrets"`
+	Threshold int         `mapstructure:"threshold"`
+	// RotationStateFile records the keys activated by the admin rotate endpoint, they stay active after a restart
+	RotationStateFile string `mapstructure:"rotation_state_file"`
+}
+
+// Keys returns the configured approver keys.
+func (c SecretConfig) Keys() []KeyConfig {
+	if len(c.Secrets) == 0 {
+		return []KeyConfig{c.KeyConfig}
+	}
+	return c.Secrets
+}
+
+type KeyConfig struct {
 	Type                   string                 `mapstructure:"type"`
 	LocalSecretConfig      LocalSecretConfig      `mapstructure:"local_secret"`
 	AWSSecretManagerConfig AWSSecretManagerConfig `mapstructure:"aws_secret_manager"`
+	KeystoreConfig         KeystoreConfig         `mapstructure:"keystore"`
+	VaultConfig            VaultConfig            `mapstructure:"vault"`
+	Web3SignerConfig       Web3SignerConfig       `mapstructure:"web3signer"`
+	PKCS11Config           PKCS11Config           `mapstructure:"pkcs11"`
+	AWSKMSConfig           AWSKMSConfig           `mapstructure:"aws_kms"`
+	UnixSocketConfig       UnixSocketConfig       `mapstructure:"unix_socket"`
+	// Next is the key to rotate to, it is activated at ActivationTime (RFC 3339) or by the admin rotate endpoint
+	Next           *KeyConfig `mapstructure:"next"`
+	ActivationTime string     `mapstructure:"activation_time"`
 }
 
 type LocalSecretConfig struct {
@@ -100,11 +133,85 @@ type AWSSecretManagerConfig struct {
 	SecretName string `mapstructure:"secret_name"`
 }
 
+type UnixSocketConfig struct {
+	Path    string        `mapstructure:"path"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type AWSKMSConfig struct {
+	Region string `mapstructure:"region"`
+	KeyID  string `mapstructure:"key_id"`
+}
+
+type KeystoreConfig struct {
+	KeyFile        string `mapstructure:"key_file"`
+	PassphraseFile string `mapstructure:"passphrase_file"`
+	PassphraseEnv  string `mapstructure:"passphrase_env"`
+}
+
+type VaultConfig struct {
+	Address          string        `mapstructure:"address"`
+	Namespace        string        `mapstructure:"namespace"`
+	Token            string        `mapstructure:"token"`
+	AppRoleMountPath string        `mapstructure:"approle_mount_path"`
+	RoleID           string        `mapstructure:"role_id"`
+	SecretID         string        `mapstructure:"secret_id"`
+	MountPath        string        `mapstructure:"mount_path"`
+	KeyName          string        `mapstructure:"key_name"`
+	Timeout          time.Duration `mapstructure:"timeout"`
+}
+
+type Web3SignerConfig struct {
+	URL         string        `mapstructure:"url"`
+	Address     string        `mapstructure:"address"`
+	CACert      string        `mapstructure:"ca_cert"`
+	ClientCert  string        `mapstructure:"client_cert"`
+	ClientKey   string        `mapstructure:"client_key"`
+	DialTimeout time.Duration `mapstructure:"dial_timeout"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+}
+
+type PKCS11Config struct {
+	Module     string `mapstructure:"module"`
+	TokenLabel string `mapstructure:"token_label"`
+	PIN        string `mapstructure:"pin"`
+	KeyLabel   string `mapstructure:"key_label"`
+}
+
 func defaultSecretConfig(v *viper.Viper) {
+	v.SetDefault("secret.threshold", 1)
+	v.SetDefault("secret.rotation_state_file", "")
 	v.SetDefault("secret.type", "local")
 	v.SetDefault("secret.local_secret.private_key", "")
 	v.SetDefault("secret.aws_secret_manager.region", "")
 	v.SetDefault("secret.aws_secret_manager.secret_name", "")
+	v.SetDefault("secret.aws_kms.region", "")
+	v.SetDefault("secret.aws_kms.key_id", "")
+	v.SetDefault("secret.unix_socket.path", "/var/run/token-recover-approver/signer.sock")
+	v.SetDefault("secret.unix_socket.timeout", 5*time.Second)
+	v.SetDefault("secret.keystore.key_file", "")
+	v.SetDefault("secret.keystore.passphrase_file", "")
+	v.SetDefault("secret.keystore.passphrase_env", "")
+	v.SetDefault("secret.vault.address", "http://127.0.0.1:8200")
+	v.SetDefault("secret.vault.namespace", "")
+	v.SetDefault("secret.vault.token", "")
+	v.SetDefault("secret.vault.approle_mount_path", "approle")
+	v.SetDefault("secret.vault.role_id", "")
+	v.SetDefault("secret.vault.secret_id", "")
+	v.SetDefault("secret.vault.mount_path", "transit")
+	v.SetDefault("secret.vault.key_name", "")
+	v.SetDefault("secret.vault.timeout", 10*time.Second)
+	v.SetDefault("secret.web3signer.url", "http://127.0.0.1:9000")
+	v.SetDefault("secret.web3signer.address", "")
+	v.SetDefault("secret.web3signer.ca_cert", "")
+	v.SetDefault("secret.web3signer.client_cert", "")
+	v.SetDefault("secret.web3signer.client_key", "")
+	v.SetDefault("secret.web3signer.dial_timeout", 5*time.Second)
+	v.SetDefault("secret.web3signer.timeout", 10*time.Second)
+	v.SetDefault("secret.pkcs11.module", "")
+	v.SetDefault("secret.pkcs11.token_label", "")
+	v.SetDefault("secret.pkcs11.pin", "")
+	v.SetDefault("secret.pkcs11.key_label", "")
 }
 
 type StoreConfig struct {
@@ -163,6 +270,68 @@ func defaultStoreConfig(v *viper.Viper) {
 
 }
 
+type ApprovalConfig struct {
+	// Policy decides how a repeated request for an already approved token is handled
+	Policy     string         `mapstructure:"policy"`
+	SignScheme string         `mapstructure:"sign_scheme"`
+	EIP712     EIP712Config   `mapstructure:"eip712"`
+	Deadline   DeadlineConfig `mapstructure:"deadline"`
+	// IntegrityCheck verifies every proof in the store against the merkle root at startup
+	IntegrityCheck IntegrityCheckConfig `mapstructure:"integrity_check"`
+}
+
+// EIP712Config is the EIP-712 domain of the approval signature
+type EIP712Config struct {
+	Name              string `mapstructure:"name"`
+	Version           string `mapstructure:"version"`
+	ChainID           uint64 `mapstructure:"chain_id"` // BSC chain id
+	VerifyingContract string `mapstructure:"verifying_contract"`
+}
+
+// DeadlineConfig limits the validity of an approval, the deadline is signed with the approval
+type DeadlineConfig struct {
+	Enable bool          `mapstructure:"enable"`
+	TTL    time.Duration `mapstructure:"ttl"`
+}
+
+// IntegrityCheckConfig refuses to start once more than MaxMismatches proofs don't match the merkle root,
+// the progress is logged every ProgressInterval proofs.
+type IntegrityCheckConfig struct {
+	Enable           bool  `mapstructure:"enable"`
+	MaxMismatches    int64 `mapstructure:"max_mismatches"`
+	ProgressInterval int64 `mapstructure:"progress_interval"`
+}
+
+func defaultApprovalConfig(v *viper.Viper) {
+	v.SetDefault("approval.policy", "reissue")
+	v.SetDefault("approval.sign_scheme", "legacy")
+	v.SetDefault("approval.eip712.name", "TokenRecoverPortal")
+	v.SetDefault("approval.eip712.version", "1")
+	v.SetDefault("approval.eip712.chain_id", 97)
+	v.SetDefault("approval.eip712.verifying_contract", "0x0000000000000000000000000000000000003000")
+	v.SetDefault("approval.deadline.enable", false)
+	v.SetDefault("approval.deadline.ttl", 24*time.Hour)
+	v.SetDefault("approval.integrity_check.enable", false)
+	v.SetDefault("approval.integrity_check.max_mismatches", 0)
+	v.SetDefault("approval.integrity_check.progress_interval", 100000)
+}
+
+// SignerConfig is the config of the signer daemon, which signs with the key configured in secret.
+type SignerConfig struct {
+	SocketPath string `mapstructure:"socket_path"`
+	// SocketMode is the octal permission of the socket file
+	SocketMode  string        `mapstructure:"socket_mode"`
+	AllowedUIDs []uint32      `mapstructure:"allowed_uids"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+}
+
+func defaultSignerConfig(v *viper.Viper) {
+	v.SetDefault("signer.socket_path", "/var/run/token-recover-approver/signer.sock")
+	v.SetDefault("signer.socket_mode", "0600")
+	v.SetDefault("signer.allowed_uids", []uint32{})
+	v.SetDefault("signer.timeout", 5*time.Second)
+}
+
 func NewConfig(configPath string) (*Config, error) {
 	var file *os.File
 	file, err := os.Open(configPath)
@@ -181,6 +350,8 @@ func NewConfig(configPath string) (*Config, error) {
 	defaultMetricsConfig(v)
 	defaultSecretConfig(v)
 	defaultStoreConfig(v)
+	defaultApprovalConfig(v)
+	defaultSignerConfig(v)
 
 	// note: environment variables will override config file
 	// note: environment variables should be in uppercase
@@ -2,11 +2,21 @@ package injection
 
 import (
 	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
 
 	"github.com/bnb-chain/token-recover-approver/internal/config"
 	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
 	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/aws"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/keystore"
 	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/local"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/pkcs11"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/unixsocket"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/vault"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/web3signer"
 )
 
 type SecretType string
@@ -14,15 +24,115 @@ type SecretType string
 const (
 	LocalKey         SecretType = "local"
 	AWSSecretManager SecretType = "aws"
+	AWSKMS           SecretType = "aws_kms"
+	Keystore         SecretType = "keystore"
+	Vault            SecretType = "vault"
+	Web3Signer       SecretType = "web3signer"
+	PKCS11           SecretType = "pkcs11"
+	UnixSocket       SecretType = "unix_socket"
 )
 
-func InitKeyManager(config *config.Config) (keymanager.KeyManager, error) {
-	switch SecretType(config.Secret.Type) {
+func InitKeyManager(config config.KeyConfig) (keymanager.KeyManager, error) {
+	km, err := initKeyManager(config)
+	if err != nil || config.Next == nil {
+		return km, err
+	}
+
+	next, err := initKeyManager(*config.Next)
+	if err != nil {
+		return nil, fmt.Errorf("next key: %w", err)
+	}
+	var activateAt time.Time
+	if config.ActivationTime != "" {
+		activateAt, err = time.Parse(time.RFC3339, config.ActivationTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid activation time: %w", err)
+		}
+	}
+	return keymanager.NewRotatingKeyManager(km, next, activateAt), nil
+}
+
+func initKeyManager(config config.KeyConfig) (keymanager.KeyManager, error) {
+	switch SecretType(config.Type) {
 	case LocalKey:
-		return local.NewLocalKeyManager(config.Secret.LocalSecretConfig.PrivateKey)
+		return local.NewLocalKeyManager(config.LocalSecretConfig.PrivateKey)
 	case AWSSecretManager:
-		return aws.NewSecretManager(config.Secret.AWSSecretManagerConfig.SecretName, config.Secret.AWSSecretManagerConfig.Region)
+		return aws.NewSecretManager(config.AWSSecretManagerConfig.SecretName, config.AWSSecretManagerConfig.Region)
+	case UnixSocket:
+		return unixsocket.NewUnixSocketKeyManager(config.UnixSocketConfig.Path, config.UnixSocketConfig.Timeout)
+	case AWSKMS:
+		return aws.NewKMSKeyManager(config.AWSKMSConfig.KeyID, config.AWSKMSConfig.Region)
+	case Keystore:
+		passphrase, err := keystore.ReadPassphrase(config.KeystoreConfig.PassphraseFile, config.KeystoreConfig.PassphraseEnv)
+		if err != nil {
+			return nil, err
+		}
+		return keystore.NewKeystoreKeyManager(config.KeystoreConfig.KeyFile, passphrase)
+	case Vault:
+		return vault.NewVaultKeyManager(vault.Config{
+			Address:          config.VaultConfig.Address,
+			Namespace:        config.VaultConfig.Namespace,
+			Token:            config.VaultConfig.Token,
+			AppRoleMountPath: config.VaultConfig.AppRoleMountPath,
+			RoleID:           config.VaultConfig.RoleID,
+			SecretID:         config.VaultConfig.SecretID,
+			MountPath:        config.VaultConfig.MountPath,
+			KeyName:          config.VaultConfig.KeyName,
+			Timeout:          config.VaultConfig.Timeout,
+		})
+	case Web3Signer:
+		if !common.IsHexAddress(config.Web3SignerConfig.Address) {
+			return nil, errors.New("invalid web3signer address")
+		}
+		return web3signer.NewWeb3SignerKeyManager(web3signer.Config{
+			URL:         config.Web3SignerConfig.URL,
+			Address:     common.HexToAddress(config.Web3SignerConfig.Address),
+			CACert:      config.Web3SignerConfig.CACert,
+			ClientCert:  config.Web3SignerConfig.ClientCert,
+			ClientKey:   config.Web3SignerConfig.ClientKey,
+			DialTimeout: config.Web3SignerConfig.DialTimeout,
+			Timeout:     config.Web3SignerConfig.Timeout,
+		})
+	case PKCS11:
+		return pkcs11.NewPKCS11KeyManager(pkcs11.Config{
+			Module:     config.PKCS11Config.Module,
+			TokenLabel: config.PKCS11Config.TokenLabel,
+			PIN:        config.PKCS11Config.PIN,
+			KeyLabel:   config.PKCS11Config.KeyLabel,
+		})
 	default:
 		return nil, errors.New("invalid secret type")
 	}
 }
+
+func InitThresholdSigner(config *config.Config, logger *zerolog.Logger) (*keymanager.ThresholdSigner, error) {
+	keys := config.Secret.Keys()
+	kms := make([]keymanager.KeyManager, 0, len(keys))
+	for _, key := range keys {
+		km, err := InitKeyManager(key)
+		if err != nil {
+			return nil, err
+		}
+		kms = append(kms, km)
+	}
+
+	signer, err := keymanager.NewThresholdSigner(config.Secret.Threshold, kms...)
+	if err != nil {
+		return nil, err
+	}
+	if config.Secret.RotationStateFile != "" {
+		rotations, err := keymanager.LoadRotations(config.Secret.RotationStateFile)
+		if err != nil {
+			return nil, fmt.Errorf("load rotation state: %w", err)
+		}
+		if err := signer.Restore(rotations); err != nil {
+			return nil, fmt.Errorf("restore rotation state: %w", err)
+		}
+	}
+	logger.Info().
+		Interface("signers", signer.Addresses()).
+		Int("threshold", signer.Threshold()).
+		Interface("rotations", signer.Rotations()).
+		Msgf("approver signers: %d of %d", signer.Threshold(), len(kms))
+	return signer, nil
+}
@@ -1,6 +1,8 @@
 package injection
 
 import (
+	"os"
+
 	"github.com/rs/zerolog"
 
 	"github.com/bnb-chain/token-recover-approver/internal/config"
@@ -11,3 +13,8 @@ import (
 func InitLogger(config *config.Config) (*zerolog.Logger, error) {
 	return logger.NewLogger(config.Logger.Level, config.Logger.Format, logger.WithStr("app_id", version.APPName))
 }
+
+// InitToolLogger logs to stderr, the tools write their output to stdout.
+func InitToolLogger(config *config.Config) (*zerolog.Logger, error) {
+	return logger.NewLoggerWithWriter(config.Logger.Level, config.Logger.Format, os.Stderr, logger.WithStr("app_id", version.APPName))
+}
@@ -0,0 +1,46 @@
+package injection
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bnb-chain/token-recover-approver/internal/config"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/unixsocket"
+)
+
+func InitSignerServer(config *config.Config, logger *zerolog.Logger) (*unixsocket.Server, error) {
+	if SecretType(config.Secret.Type) == UnixSocket {
+		return nil, errors.New("signer daemon can not sign with a unix_socket secret")
+	}
+	if len(config.Secret.Secrets) > 0 {
+		return nil, errors.New("signer daemon signs with a single key, secret.secrets is not supported")
+	}
+	if config.Secret.Next != nil {
+		return nil, errors.New("signer daemon can not rotate keys, secret.next is not supported")
+	}
+	mode, err := strconv.ParseUint(config.Signer.SocketMode, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer socket mode: %w", err)
+	}
+	km, err := InitKeyManager(config.Secret.KeyConfig)
+	if err != nil {
+		return nil, err
+	}
+	// serve the uid of the daemon only, unless other uids are allowed
+	allowedUIDs := config.Signer.AllowedUIDs
+	if len(allowedUIDs) == 0 {
+		allowedUIDs = []uint32{uint32(os.Getuid())}
+	}
+
+	logger.Info().
+		Str("signer", km.Address().Hex()).
+		Str("socket_path", config.Signer.SocketPath).
+		Str("socket_mode", config.Signer.SocketMode).
+		Interface("allowed_uids", allowedUIDs).
+		Msg("signer daemon")
+	return unixsocket.NewServer(km, config.Signer.SocketPath, os.FileMode(mode), allowedUIDs, config.Signer.Timeout, logger), nil
+}
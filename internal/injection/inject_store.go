@@ -2,6 +2,7 @@ package injection
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/rs/zerolog"
 	gormLogger "gorm.io/gorm/logger"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/bnb-chain/token-recover-approver/internal/common"
 	"github.com/bnb-chain/token-recover-approver/internal/config"
+	"github.com/bnb-chain/token-recover-approver/internal/module/approval"
 	"github.com/bnb-chain/token-recover-approver/internal/store"
 	"github.com/bnb-chain/token-recover-approver/internal/store/gorm"
 	"github.com/bnb-chain/token-recover-approver/internal/store/memory"
@@ -22,7 +24,8 @@ const (
 	GORMStore   StoreType = "gorm"
 )
 
-func initSDK(config *config.Config, logger *zerolog.Logger) {
+// InitSDK sets the bech32 prefixes of the chain, it must run before any address is decoded.
+func InitSDK(config *config.Config, logger *zerolog.Logger) {
 	logger.Info().Str("chain_id", config.ChainID).Msg("init sdk config")
 	sdkConfig := types.GetConfig()
 	sdkConfig.SetBech32PrefixForAccount("bnb", "bnbp")
@@ -33,22 +36,56 @@ func initSDK(config *config.Config, logger *zerolog.Logger) {
 }
 
 func InitStore(config *config.Config, logger *zerolog.Logger) (store.Store, error) {
-	initSDK(config, logger)
+	InitSDK(config, logger)
 	switch StoreType(config.Store.Driver) {
 	case MemoryStore:
 		return memory.NewMemoryStore(
 			config.Store.MemoryStore.MerkleProofs,
 		)
 	case GORMStore:
-		return gorm.NewSQLStore(
-			config,
-			gorm.SetConnMaxLifetime(config.Store.SqlStore.MaxLifetime),
-			gorm.SetConnMaxIdleTime(config.Store.SqlStore.MaxIdleTime),
-			gorm.SetMaxIdleConns(config.Store.SqlStore.MaxIdleConn),
-			gorm.SetMaxOpenConns(config.Store.SqlStore.MaxOpenConn),
-			gorm.SetLogLevel(gormLogger.LogLevel(config.Store.SqlStore.LogLevel)),
+		return gorm.NewSQLStore(config, sqlStoreOptions(config)...)
+	default:
+		return nil, errors.New("invalid store type")
+	}
+}
+
+// InitStoreWithoutMigration opens the store like InitStore, but never migrates the database.
+func InitStoreWithoutMigration(config *config.Config, logger *zerolog.Logger) (store.Store, error) {
+	InitSDK(config, logger)
+	switch StoreType(config.Store.Driver) {
+	case MemoryStore:
+		return memory.NewMemoryStore(
+			config.Store.MemoryStore.MerkleProofs,
 		)
+	case GORMStore:
+		return gorm.OpenSQLStore(config, sqlStoreOptions(config)...)
 	default:
 		return nil, errors.New("invalid store type")
 	}
 }
+
+func sqlStoreOptions(config *config.Config) []gorm.Option {
+	return []gorm.Option{
+		gorm.SetConnMaxLifetime(config.Store.SqlStore.MaxLifetime),
+		gorm.SetConnMaxIdleTime(config.Store.SqlStore.MaxIdleTime),
+		gorm.SetMaxIdleConns(config.Store.SqlStore.MaxIdleConn),
+		gorm.SetMaxOpenConns(config.Store.SqlStore.MaxOpenConn),
+		gorm.SetLogLevel(gormLogger.LogLevel(config.Store.SqlStore.LogLevel)),
+	}
+}
+
+// InitApprovalStore returns the approval ledger of the store, the memory store loses its ledger on restart
+// so it is refused for the policies which depend on the approvals issued before.
+func InitApprovalStore(config *config.Config, s store.Store) (store.ApprovalStore, error) {
+	if StoreType(config.Store.Driver) == MemoryStore {
+		switch approval.LedgerPolicy(config.Approval.Policy) {
+		case approval.ReplayPolicy, approval.RejectPolicy:
+			return nil, fmt.Errorf("approval policy %s needs the gorm store driver, the memory store loses its approval ledger on restart", config.Approval.Policy)
+		}
+	}
+	approvalStore, ok := s.(store.ApprovalStore)
+	if !ok {
+		return nil, errors.New("store does not support approval ledger")
+	}
+	return approvalStore, nil
+}
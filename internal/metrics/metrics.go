@@ -1,8 +1,17 @@
 package metrics
 
+// Outcomes of a batch approval item
+const (
+	SuccessOutcome = "success"
+	FailureOutcome = "failure"
+)
+
 type Metrics interface {
 	IncApprovalCount()
 	IncApprovalErrorCount()
+	IncBatchApprovalItemCount(outcome string)
+	SetActiveSigners(addresses []string)
+	SetIntegrityCheckProgress(checked, mismatched int64)
 	ObserveApprovalDuration(time float64)
 	ObserveMerkleProofVerificationDuration(time float64)
 	ObserveGetProofDataDuration(time float64)
@@ -35,6 +35,18 @@ func NewCollector(registry *prometheus.Registry) *Collector {
 		Name: "approval_error_count",
 		Help: "The total number of approval errors.",
 	})
+	batchApprovalItemCount := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "batch_approval_item_count",
+		Help: "The total number of batch approval items by outcome.",
+	}, []string{"outcome"})
+	activeSigner := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "active_signer",
+		Help: "The active approver signer addresses, set to 1 for every active signer.",
+	}, []string{"address"})
+	integrityCheckProofs := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "integrity_check_proofs",
+		Help: "The number of proofs checked against the merkle root at startup by result.",
+	}, []string{"result"})
 	approvalDuration := prometheus.NewSummary(
 		prometheus.SummaryOpts{
 			Name: "approval_duration_seconds",
@@ -79,6 +91,9 @@ func NewCollector(registry *prometheus.Registry) *Collector {
 		versionInfo,
 		approvalCount,
 		approvalErrorCount,
+		batchApprovalItemCount,
+		activeSigner,
+		integrityCheckProofs,
 		approvalDuration,
 		getProofDataDuration,
 		merkleProofVerificationDuration,
@@ -87,6 +102,9 @@ func NewCollector(registry *prometheus.Registry) *Collector {
 	return &Collector{
 		approvalCount:                   approvalCount,
 		approvalErrorCount:              approvalErrorCount,
+		batchApprovalItemCount:          batchApprovalItemCount,
+		activeSigner:                    activeSigner,
+		integrityCheckProofs:            integrityCheckProofs,
 		approvalDuration:                approvalDuration,
 		getProofDataDuration:            getProofDataDuration,
 		merkleProofVerificationDuration: merkleProofVerificationDuration,
@@ -96,6 +114,9 @@ func NewCollector(registry *prometheus.Registry) *Collector {
 type Collector struct {
 	approvalCount                   prometheus.Counter
 	approvalErrorCount              prometheus.Counter
+	batchApprovalItemCount          *prometheus.CounterVec
+	activeSigner                    *prometheus.GaugeVec
+	integrityCheckProofs            *prometheus.GaugeVec
 	approvalDuration                prometheus.Summary
 	getProofDataDuration            prometheus.Summary
 	merkleProofVerificationDuration prometheus.Summary
@@ -125,3 +146,22 @@ func (c *Collector) IncApprovalCount() {
 func (c *Collector) IncApprovalErrorCount() {
 	c.approvalErrorCount.Inc()
 }
+
+// IncBatchApprovalItemCount implements metrics.Metrics.
+func (c *Collector) IncBatchApprovalItemCount(outcome string) {
+	c.batchApprovalItemCount.WithLabelValues(outcome).Inc()
+}
+
+// SetActiveSigners implements metrics.Metrics.
+func (c *Collector) SetActiveSigners(addresses []string) {
+	c.activeSigner.Reset()
+	for _, address := range addresses {
+		c.activeSigner.WithLabelValues(address).Set(1)
+	}
+}
+
+// SetIntegrityCheckProgress implements metrics.Metrics.
+func (c *Collector) SetIntegrityCheckProgress(checked, mismatched int64) {
+	c.integrityCheckProofs.WithLabelValues("checked").Set(float64(checked))
+	c.integrityCheckProofs.WithLabelValues("mismatched").Set(float64(mismatched))
+}
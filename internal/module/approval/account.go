@@ -0,0 +1,54 @@
+package approval
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types"
+)
+
+// GetAccountAssets returns every asset of the account that can be recovered.
+func (svc *ApprovalService) GetAccountAssets(address types.AccAddress) (*GetAccountAssetsResponse, error) {
+	getProofsStartTime := time.Now()
+	proofs, err := svc.store.ListAccountAssetProofs(address)
+	if err != nil {
+		return nil, err
+	}
+	svc.metrics.ObserveGetProofDataDuration(float64(time.Since(getProofsStartTime).Seconds()))
+
+	assets := make([]*Asset, 0, len(proofs))
+	for _, proof := range proofs {
+		assets = append(assets, &Asset{
+			Denom:  proof.Denom,
+			Amount: big.NewInt(proof.Amount),
+		})
+	}
+
+	return &GetAccountAssetsResponse{
+		Address: address,
+		Assets:  assets,
+	}, nil
+}
+
+// GetAccountAssetProof returns the merkle leaf and proof of the account asset.
+func (svc *ApprovalService) GetAccountAssetProof(address types.AccAddress, symbol string) (*GetAccountAssetProofResponse, error) {
+	getProofsStartTime := time.Now()
+	proof, err := svc.store.GetAccountAssetProof(address, symbol)
+	if err != nil {
+		return nil, err
+	}
+	svc.metrics.ObserveGetProofDataDuration(float64(time.Since(getProofsStartTime).Seconds()))
+
+	leaf, err := proof.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetAccountAssetProofResponse{
+		Address: address,
+		Denom:   proof.Denom,
+		Amount:  big.NewInt(proof.Amount),
+		Leaf:    leaf,
+		Proofs:  proof.Proof,
+	}, nil
+}
@@ -1,12 +1,12 @@
 package approval
 
 import (
-	"errors"
-	"math/big"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/rs/zerolog"
@@ -26,15 +26,18 @@ import (
 type ApprovalService struct {
 	config           *config.Config
 	merkleRoot       []byte
-	km               keymanager.KeyManager
+	signer           *keymanager.ThresholdSigner
 	store            store.Store
+	approvalStore    store.ApprovalStore
+	policy           LedgerPolicy
+	digester         Digester
 	accountWhiteList map[string]struct{}
 
 	metrics metrics.Metrics
 	logger  *zerolog.Logger
 }
 
-func NewApprovalService(config *config.Config, km keymanager.KeyManager, store store.Store, metrics metrics.Metrics, logger *zerolog.Logger) (*ApprovalService, error) {
+func NewApprovalService(config *config.Config, signer *keymanager.ThresholdSigner, store store.Store, approvalStore store.ApprovalStore, metrics metrics.Metrics, logger *zerolog.Logger) (*ApprovalService, error) {
 	accountWhiteList := make(map[string]struct{})
 	for _, addr := range config.AccountWhiteList {
 		accountWhiteList[addr] = struct{}{}
@@ -43,7 +46,28 @@ func NewApprovalService(config *config.Config, km keymanager.KeyManager, store s
 	if err != nil {
 		return nil, err
 	}
-	return &ApprovalService{km: km, store: store, config: config, merkleRoot: merkleRoot, accountWhiteList: accountWhiteList, metrics: metrics, logger: logger}, nil
+	policy := LedgerPolicy(config.Approval.Policy)
+	if policy == "" {
+		policy = ReissuePolicy
+	}
+	if _, ok := supportedLedgerPolicies[policy]; !ok {
+		return nil, fmt.Errorf("unsupported approval policy: %s", config.Approval.Policy)
+	}
+	if config.Approval.Deadline.Enable && config.Approval.Deadline.TTL <= 0 {
+		return nil, fmt.Errorf("invalid approval deadline ttl: %s", config.Approval.Deadline.TTL)
+	}
+	digester, err := NewDigester(&config.Approval)
+	if err != nil {
+		return nil, err
+	}
+	svc := &ApprovalService{signer: signer, store: store, approvalStore: approvalStore, policy: policy, digester: digester, config: config, merkleRoot: merkleRoot, accountWhiteList: accountWhiteList, metrics: metrics, logger: logger}
+	svc.updateActiveSigners()
+	if config.Approval.IntegrityCheck.Enable {
+		if _, err := svc.CheckIntegrity(); err != nil {
+			return nil, err
+		}
+	}
+	return svc, nil
 }
 
 func (svc *ApprovalService) checkWhiteList(acc types.AccAddress) bool {
@@ -60,7 +84,7 @@ func (svc *ApprovalService) GetTokenRecoverApproval(req *GetTokenRecoverApproval
 	ownerPubKeyBytes, err := hexutil.Decode(req.OwnerPubKey)
 	if err != nil {
 		svc.metrics.IncApprovalErrorCount()
-		return nil, err
+		return nil, fmt.Errorf("%w: decode owner public key: %v", ErrInvalidRequest, err)
 	}
 	ownerAddr, err := svc.getAddressFromPubKey(ownerPubKeyBytes)
 	if err != nil {
@@ -70,14 +94,14 @@ func (svc *ApprovalService) GetTokenRecoverApproval(req *GetTokenRecoverApproval
 	ownerSignature, err := hexutil.Decode(req.OwnerSignature)
 	if err != nil {
 		svc.metrics.IncApprovalErrorCount()
-		return nil, err
+		return nil, fmt.Errorf("%w: decode owner signature: %v", ErrInvalidRequest, err)
 	}
 
 	svc.logger.Info().Str("address", ownerAddr.String()).Msg("GetTokenRecoverApproval")
 	// Check While List
 	if !svc.checkWhiteList(ownerAddr) {
 		svc.metrics.IncApprovalErrorCount()
-		return nil, errors.New("address is not in while list")
+		return nil, ErrNotWhitelisted
 	}
 
 	// Get Merkle Proofs and Node
@@ -93,11 +117,10 @@ func (svc *ApprovalService) GetTokenRecoverApproval(req *GetTokenRecoverApproval
 	// Check if token amount is zero
 	if proof.Amount == 0 {
 		svc.metrics.IncApprovalErrorCount()
-		return nil, errors.New("token amount is zero")
+		return nil, ErrZeroAmount
 	}
 	// Verify user signature
-	tokenRecoverRequestMsg := recover.NewTokenRecoverRequestMsg(req.TokenSymbol, uint64(proof.Amount), strings.ToLower(req.ClaimAddress.Hex()))
-	msgBytes, err := svc.getStdMsgBytes(tokenRecoverRequestMsg)
+	msgBytes, err := TokenRecoverRequestMsgBytes(svc.config.ChainID, req.TokenSymbol, proof.Amount, req.ClaimAddress)
 	if err != nil {
 		svc.metrics.IncApprovalErrorCount()
 		return nil, err
@@ -120,37 +143,192 @@ func (svc *ApprovalService) GetTokenRecoverApproval(req *GetTokenRecoverApproval
 	merkleProofVerificationStartTime := time.Now()
 	if !util.VerifyMerkleProof(svc.merkleRoot, proof.Proof, nodeBytes) {
 		svc.metrics.IncApprovalErrorCount()
-		return nil, errors.New("verify merkle proof failed")
+		return nil, ErrInvalidMerkleProof
 	}
 	svc.metrics.ObserveMerkleProofVerificationDuration(float64(time.Since(merkleProofVerificationStartTime).Seconds()))
 
+	// Check approval ledger
+	now := time.Now()
+	issued, err := svc.findIssuedApproval(ownerAddr, req.TokenSymbol, proof.Amount, req.ClaimAddress, now)
+	if err != nil {
+		svc.metrics.IncApprovalErrorCount()
+		return nil, err
+	}
+	if issued != nil {
+		svc.logger.Info().Str("address", ownerAddr.String()).Str("symbol", req.TokenSymbol).Msg("Replay issued approval")
+		svc.metrics.IncApprovalCount()
+		svc.metrics.ObserveApprovalDuration(float64(time.Since(approvalStartTime).Seconds()))
+		return newGetTokenRecoverApprovalResponse(proof, issued.ApprovalSignatures, issued.Signers, issued.Deadline), nil
+	}
+	if err := svc.reserveClaim(ownerAddr, req.TokenSymbol, req.ClaimAddress); err != nil {
+		svc.metrics.IncApprovalErrorCount()
+		return nil, err
+	}
+
 	// Sign ApprovalSignature
-	signData := make([][]byte, 0, len(proof.Proof)+5)
-	signData = append(signData, [][]byte{
-		[]byte(svc.config.ChainID), req.ClaimAddress[:], ownerSignature, nodeBytes,
-		svc.merkleRoot,
-	}...)
-	signData = append(signData, proof.Proof...)
-
-	approvalSignature, err := svc.km.Sign(crypto.Keccak256(signData...))
+	var deadline uint64
+	if svc.config.Approval.Deadline.Enable {
+		deadline = uint64(now.Add(svc.config.Approval.Deadline.TTL).Unix())
+	}
+	digest := svc.digester.Digest(&ApprovalPayload{
+		ChainID:        svc.config.ChainID,
+		ClaimAddress:   req.ClaimAddress,
+		OwnerSignature: ownerSignature,
+		Leaf:           nodeBytes,
+		MerkleRoot:     svc.merkleRoot,
+		Proof:          proof.Proof,
+		Deadline:       deadline,
+	})
+	approvalSignatures, signers, err := svc.signer.Sign(digest)
+	if err != nil {
+		svc.metrics.IncApprovalErrorCount()
+		return nil, err
+	}
+	svc.logger.Debug().
+		Strs("approval_signatures", util.EncodeBytesArrayToHex(approvalSignatures)).
+		Interface("signers", signers).
+		Msg("Signed ApprovalSignature")
+	svc.updateActiveSigners()
+
+	// Record approval
+	err = svc.approvalStore.InsertApproval(&store.Approval{
+		Address:            ownerAddr,
+		Denom:              req.TokenSymbol,
+		Amount:             proof.Amount,
+		ClaimAddress:       req.ClaimAddress,
+		OwnerSignatureHash: crypto.Keccak256(ownerSignature),
+		ApprovalSignatures: approvalSignatures,
+		Signers:            signers,
+		Deadline:           deadline,
+	})
 	if err != nil {
 		svc.metrics.IncApprovalErrorCount()
 		return nil, err
 	}
-	svc.logger.Debug().Str("approval_signature", hexutil.Encode(approvalSignature)).Msg("Signed ApprovalSignature")
 
 	svc.metrics.IncApprovalCount()
 	svc.metrics.ObserveApprovalDuration(float64(time.Since(approvalStartTime).Seconds()))
-	return &GetTokenRecoverApprovalResponse{
-		Amount:            big.NewInt(proof.Amount),
-		Proofs:            proof.Proof,
-		ApprovalSignature: approvalSignature,
-	}, nil
+	return newGetTokenRecoverApprovalResponse(proof, approvalSignatures, signers, deadline), nil
+}
+
+// GetTokenRecoverApprovalBatch approves every item of the batch, a failed item does not abort the rest.
+func (svc *ApprovalService) GetTokenRecoverApprovalBatch(req *GetTokenRecoverApprovalBatchRequest) (*GetTokenRecoverApprovalBatchResponse, error) {
+	resp := &GetTokenRecoverApprovalBatchResponse{
+		Items: make([]*TokenRecoverApprovalResult, 0, len(req.Items)),
+	}
+	for _, item := range req.Items {
+		result := &TokenRecoverApprovalResult{TokenSymbol: item.TokenSymbol}
+		resp.Items = append(resp.Items, result)
+
+		itemReq := req.Request(item)
+		err := itemReq.Validate()
+		if err == nil {
+			result.Approval, err = svc.GetTokenRecoverApproval(itemReq)
+		}
+		if err != nil {
+			svc.logger.Debug().Str("symbol", item.TokenSymbol).Err(err).Msg("GetTokenRecoverApprovalBatch item failed")
+			svc.metrics.IncBatchApprovalItemCount(metrics.FailureOutcome)
+			result.Err = err
+			continue
+		}
+		svc.metrics.IncBatchApprovalItemCount(metrics.SuccessOutcome)
+	}
+
+	return resp, nil
+}
+
+// findIssuedApproval applies the ledger policy to the approvals already issued for the account asset,
+// it returns the approval to replay or nil if a new one should be signed.
+func (svc *ApprovalService) findIssuedApproval(ownerAddr types.AccAddress, symbol string, amount int64, claimAddress common.Address, now time.Time) (*store.Approval, error) {
+	if svc.policy == ReissuePolicy {
+		return nil, nil
+	}
+
+	approvals, err := svc.approvalStore.GetApprovals(ownerAddr, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var issued *store.Approval
+	for _, approval := range approvals {
+		if approval.ClaimAddress != claimAddress {
+			if svc.policy == RejectPolicy {
+				svc.logger.Warn().
+					Str("address", ownerAddr.String()).
+					Str("symbol", symbol).
+					Str("claim_address", claimAddress.Hex()).
+					Str("issued_claim_address", approval.ClaimAddress.Hex()).
+					Msg("double claim detected")
+				return nil, ErrDoubleClaim
+			}
+			continue
+		}
+		if approval.Amount == amount && svc.isReplayable(approval, now) && svc.isSignedByCurrentSigners(approval) {
+			issued = approval
+		}
+	}
+
+	return issued, nil
+}
+
+// reserveClaim binds the account asset to the claim address under the reject policy before it is signed,
+// so that concurrent requests for different claim addresses can't both pass the ledger check.
+func (svc *ApprovalService) reserveClaim(ownerAddr types.AccAddress, symbol string, claimAddress common.Address) error {
+	if svc.policy != RejectPolicy {
+		return nil
+	}
+
+	reserved, err := svc.approvalStore.ReserveClaim(ownerAddr, symbol, claimAddress)
+	if err != nil {
+		return err
+	}
+	if reserved != claimAddress {
+		svc.logger.Warn().
+			Str("address", ownerAddr.String()).
+			Str("symbol", symbol).
+			Str("claim_address", claimAddress.Hex()).
+			Str("reserved_claim_address", reserved.Hex()).
+			Msg("double claim detected")
+		return ErrDoubleClaim
+	}
+	return nil
+}
+
+// isReplayable reports whether the issued approval is still valid under the deadline config.
+func (svc *ApprovalService) isReplayable(approval *store.Approval, now time.Time) bool {
+	if !svc.config.Approval.Deadline.Enable {
+		return approval.Deadline == 0
+	}
+	return approval.Deadline > uint64(now.Unix())
+}
+
+// isSignedByCurrentSigners reports whether the issued approval is signed by the configured signers,
+// approvals of replaced signers are not replayed.
+func (svc *ApprovalService) isSignedByCurrentSigners(approval *store.Approval) bool {
+	if len(approval.Signers) < svc.signer.Threshold() {
+		return false
+	}
+	current := make(map[common.Address]struct{})
+	for _, address := range svc.signer.Addresses() {
+		current[address] = struct{}{}
+	}
+	for _, signer := range approval.Signers {
+		if _, ok := current[signer]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// TokenRecoverRequestMsgBytes returns the std msg bytes of the token recover request, which are signed by the owner.
+func TokenRecoverRequestMsgBytes(chainID, tokenSymbol string, amount int64, claimAddress common.Address) ([]byte, error) {
+	msg := recover.NewTokenRecoverRequestMsg(tokenSymbol, uint64(amount), strings.ToLower(claimAddress.Hex()))
+	return getStdMsgBytes(chainID, msg)
 }
 
-func (svc *ApprovalService) getStdMsgBytes(msg types.Msg) ([]byte, error) {
+func getStdMsgBytes(chainID string, msg types.Msg) ([]byte, error) {
 	cdc := app.Codec
-	builder := authtxb.NewTxBuilderFromCLI().WithCodec(cdc).WithChainID(svc.config.ChainID)
+	builder := authtxb.NewTxBuilderFromCLI().WithCodec(cdc).WithChainID(chainID)
 	stdMsg, err := builder.Build([]types.Msg{msg})
 	if err != nil {
 		return nil, err
@@ -164,7 +342,7 @@ func (svc *ApprovalService) verifyTmSignature(pubKeyBytes, signatureBytes, msgBy
 
 	ok := pubKey.VerifyBytes(msgBytes, signatureBytes)
 	if !ok {
-		return errors.New("verify signature failed")
+		return ErrInvalidOwnerSignature
 	}
 	return nil
 }
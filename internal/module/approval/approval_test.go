@@ -1,41 +1,61 @@
 package approval
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"path"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/types"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
 
 	"github.com/bnb-chain/token-recover-approver/internal/config"
 	collector "github.com/bnb-chain/token-recover-approver/internal/metrics/prometheus"
 	"github.com/bnb-chain/token-recover-approver/internal/store"
 	"github.com/bnb-chain/token-recover-approver/internal/store/memory"
+	"github.com/bnb-chain/token-recover-approver/internal/version"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
 	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/local"
 	"github.com/bnb-chain/token-recover-approver/pkg/util"
 )
 
 const (
 	approvalPrivKey  = "afc2986f283cf5f9d17e04c6a12ccf8fa46149fc37d48e11abef15a46ae34eb7"
+	approvalAddress  = "0xb26859a7321AB7B2025E5E6a425D697e2eacbFB1"
 	mockDataBasePath = "../../../example/store"
 	mockMerkleRoot   = "0x59bb94f7047904a8fdaec42e4785295167f7fd63742b309afeb84bd71f8e6554"
 )
 
-func makeMockStore() (store.Store, error) {
+func makeMockStore() (*memory.MemoryStore, error) {
 	initSDK()
 	return memory.NewMemoryStore(
 		path.Join(mockDataBasePath, "merkle_proofs.json"),
 	)
 }
 
-func makeMockSvc() (*ApprovalService, error) {
-	km, err := local.NewLocalKeyManager(approvalPrivKey)
+func makeMockSvc(privKeys ...string) (*ApprovalService, error) {
+	if len(privKeys) == 0 {
+		privKeys = []string{approvalPrivKey}
+	}
+	kms := make([]keymanager.KeyManager, 0, len(privKeys))
+	for _, privKey := range privKeys {
+		km, err := local.NewLocalKeyManager(privKey)
+		if err != nil {
+			return nil, err
+		}
+		kms = append(kms, km)
+	}
+	signer, err := keymanager.NewThresholdSigner(len(kms), kms...)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +67,7 @@ func makeMockSvc() (*ApprovalService, error) {
 	return NewApprovalService(&config.Config{
 		ChainID:    "Binance-Chain-Ganges",
 		MerkleRoot: mockMerkleRoot,
-	}, km, mockStore, collector.NewCollector(prometheus.NewRegistry()), &zerolog.Logger{})
+	}, signer, mockStore, mockStore, collector.NewCollector(prometheus.NewRegistry()), &zerolog.Logger{})
 }
 
 func initSDK() {
@@ -94,7 +114,9 @@ func TestApprovalService_GetTokenRecoverApproval(t *testing.T) {
 					"0xe096d4b3669b1c7cd8fcff26b2b00029c09c0f38a34ae632b022622fb46ad69a",
 					"0x05e63b558cba63f5add60201151f96ff8f5370d2b8280a96b4fa8fd2d519ab9f",
 					"0xa2d456e52facaa953bfbc79a5a6ed7647dda59872b9b35c20183887eeb4640eb"}),
-				ApprovalSignature: util.MustDecodeHexToBytes("0x52a0a5ca80beb068d82413cac31c1df0540dc6a61eddec9f31b94419e60b6c586e5342552f4c8034a00c876d640abea8c5ba9c4d72145d0e562fedd09fe1e00a01"),
+				ApprovalSignature:  util.MustDecodeHexToBytes("0x52a0a5ca80beb068d82413cac31c1df0540dc6a61eddec9f31b94419e60b6c586e5342552f4c8034a00c876d640abea8c5ba9c4d72145d0e562fedd09fe1e00a01"),
+				ApprovalSignatures: util.MustDecodeHexArrayToBytes([]string{"0x52a0a5ca80beb068d82413cac31c1df0540dc6a61eddec9f31b94419e60b6c586e5342552f4c8034a00c876d640abea8c5ba9c4d72145d0e562fedd09fe1e00a01"}),
+				Signers:            []common.Address{common.HexToAddress(approvalAddress)},
 			},
 			wantErr: false,
 		},
@@ -127,7 +149,9 @@ func TestApprovalService_GetTokenRecoverApproval(t *testing.T) {
 					"0xe096d4b3669b1c7cd8fcff26b2b00029c09c0f38a34ae632b022622fb46ad69a",
 					"0x05e63b558cba63f5add60201151f96ff8f5370d2b8280a96b4fa8fd2d519ab9f",
 					"0xa2d456e52facaa953bfbc79a5a6ed7647dda59872b9b35c20183887eeb4640eb"}),
-				ApprovalSignature: util.MustDecodeHexToBytes("0x693ff2e0458dae7e34a8a1e9929ec122ab3f8224b3bdc0ada23d142ec5e191496fb36c172db406941c55ce715f2f32f54faffcf8551a3dfaf1be045e64a084e900"),
+				ApprovalSignature:  util.MustDecodeHexToBytes("0x693ff2e0458dae7e34a8a1e9929ec122ab3f8224b3bdc0ada23d142ec5e191496fb36c172db406941c55ce715f2f32f54faffcf8551a3dfaf1be045e64a084e900"),
+				ApprovalSignatures: util.MustDecodeHexArrayToBytes([]string{"0x693ff2e0458dae7e34a8a1e9929ec122ab3f8224b3bdc0ada23d142ec5e191496fb36c172db406941c55ce715f2f32f54faffcf8551a3dfaf1be045e64a084e900"}),
+				Signers:            []common.Address{common.HexToAddress(approvalAddress)},
 			},
 			wantErr: false,
 		},
@@ -316,10 +340,578 @@ func TestApprovalService_GetTokenRecoverApproval(t *testing.T) {
 			}...)
 			signData = append(signData, gotResp.Proofs...)
 			msgHash := crypto.Keccak256(signData...)
-			if !tt.wantErr && !svc.km.Verify(msgHash, gotResp.ApprovalSignature) {
+			if !tt.wantErr && !svc.signer.Verify(common.HexToAddress(approvalAddress), msgHash, gotResp.ApprovalSignature) {
 				t.Errorf("ApprovalService.GetTokenRecoverApproval() error = %v", fmt.Errorf("invalid approval signature"))
 				return
 			}
 		})
 	}
 }
+
+func TestApprovalService_LedgerPolicy(t *testing.T) {
+	req := &GetTokenRecoverApprovalRequest{
+		TokenSymbol:    "BNB",
+		OwnerPubKey:    "0x036d5d41cd7da2e96d39bcbd0390bfed461a86382f7a2923436ff16c65cabc7719",
+		OwnerSignature: "0x5f5391ba7f2b002b4746025f7e803a43e57a397ea66f3939d05302eb7851bbbc0773cda87aae0fbb1e2a29367b606209ed47dc5cba6d1a83f6b79cb70e56efdb",
+		ClaimAddress:   common.HexToAddress("0x2e9247B67ae885a8dcfBf77Eb6d0e93A32bea24C"),
+	}
+	ownerAddr, err := types.AccAddressFromBech32("tbnb1kufjqrefala5ylahdgv9kjk886sf556tdcs2n5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, policy := range []LedgerPolicy{ReissuePolicy, ReplayPolicy, RejectPolicy} {
+		t.Run(string(policy), func(t *testing.T) {
+			svc, err := makeMockSvc()
+			if err != nil {
+				t.Fatal(err)
+			}
+			svc.policy = policy
+
+			first, err := svc.GetTokenRecoverApproval(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := svc.GetTokenRecoverApproval(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("repeated approval = %v, want %v", second, first)
+			}
+
+			approvals, err := svc.approvalStore.GetApprovals(ownerAddr, req.TokenSymbol)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantApprovals := 1
+			if policy == ReissuePolicy {
+				wantApprovals = 2
+			}
+			if len(approvals) != wantApprovals {
+				t.Fatalf("ledger has %d approvals, want %d", len(approvals), wantApprovals)
+			}
+			if approvals[0].ClaimAddress != req.ClaimAddress || approvals[0].Amount != first.Amount.Int64() {
+				t.Errorf("unexpected ledger entry %+v", approvals[0])
+			}
+
+			// the token has been approved for another claim address
+			err = svc.approvalStore.InsertApproval(&store.Approval{
+				Address:      ownerAddr,
+				Denom:        req.TokenSymbol,
+				Amount:       first.Amount.Int64(),
+				ClaimAddress: common.HexToAddress("0x561319e67357fa3d2b51E58d011a80EB6268A0f5"),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = svc.GetTokenRecoverApproval(req)
+			if errors.Is(err, ErrDoubleClaim) != (policy == RejectPolicy) {
+				t.Errorf("ApprovalService.GetTokenRecoverApproval() error = %v, policy %s", err, policy)
+			}
+		})
+	}
+}
+
+func TestApprovalService_ReserveClaim(t *testing.T) {
+	req := &GetTokenRecoverApprovalRequest{
+		TokenSymbol:    "BNB",
+		OwnerPubKey:    "0x036d5d41cd7da2e96d39bcbd0390bfed461a86382f7a2923436ff16c65cabc7719",
+		OwnerSignature: "0x5f5391ba7f2b002b4746025f7e803a43e57a397ea66f3939d05302eb7851bbbc0773cda87aae0fbb1e2a29367b606209ed47dc5cba6d1a83f6b79cb70e56efdb",
+		ClaimAddress:   common.HexToAddress("0x2e9247B67ae885a8dcfBf77Eb6d0e93A32bea24C"),
+	}
+	ownerAddr, err := types.AccAddressFromBech32("tbnb1kufjqrefala5ylahdgv9kjk886sf556tdcs2n5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, err := makeMockSvc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.policy = RejectPolicy
+
+	// a concurrent request for another claim address has reserved the token but not recorded its approval yet
+	other := common.HexToAddress("0x561319e67357fa3d2b51E58d011a80EB6268A0f5")
+	if _, err := svc.approvalStore.ReserveClaim(ownerAddr, req.TokenSymbol, other); err != nil {
+		t.Fatal(err)
+	}
+	_, err = svc.GetTokenRecoverApproval(req)
+	if !errors.Is(err, ErrDoubleClaim) {
+		t.Fatalf("ApprovalService.GetTokenRecoverApproval() error = %v, want %v", err, ErrDoubleClaim)
+	}
+	approvals, err := svc.approvalStore.GetApprovals(ownerAddr, req.TokenSymbol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(approvals) != 0 {
+		t.Errorf("ledger has %d approvals, want 0", len(approvals))
+	}
+}
+
+func TestApprovalService_GetTokenRecoverApprovalBatch(t *testing.T) {
+	svc, err := makeMockSvc()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := svc.GetTokenRecoverApprovalBatch(&GetTokenRecoverApprovalBatchRequest{
+		OwnerPubKey:  "0x036d5d41cd7da2e96d39bcbd0390bfed461a86382f7a2923436ff16c65cabc7719",
+		ClaimAddress: common.HexToAddress("0x2e9247B67ae885a8dcfBf77Eb6d0e93A32bea24C"),
+		Items: []*TokenRecoverApprovalItem{
+			{TokenSymbol: "BNB", OwnerSignature: "0x5f5391ba7f2b002b4746025f7e803a43e57a397ea66f3939d05302eb7851bbbc0773cda87aae0fbb1e2a29367b606209ed47dc5cba6d1a83f6b79cb70e56efdb"},
+			{TokenSymbol: "BNBP", OwnerSignature: "0x5f5391ba7f2b002b4746025f7e803a43e57a397ea66f3939d05302eb7851bbbc0773cda87aae0fbb1e2a29367b606209ed47dc5cba6d1a83f6b79cb70e56efdb"},
+			{TokenSymbol: "DYTT991-49A", OwnerSignature: "0x1234"},
+			{TokenSymbol: "DYTT991-49A", OwnerSignature: "0xfe5cb16008d7afd2723cdaf16649bbbd2635dbfc2764c985847497408485f782562e7efeb7911986f4b8a74a347e49fac4c780dde80bbd4be542d51f7680cf9b"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantErrs := []error{nil, store.ErrProofNotFound, ErrInvalidRequest, nil}
+	if len(resp.Items) != len(wantErrs) {
+		t.Fatalf("got %d items, want %d", len(resp.Items), len(wantErrs))
+	}
+	for i, item := range resp.Items {
+		if !errors.Is(item.Err, wantErrs[i]) || (item.Approval == nil) != (wantErrs[i] != nil) {
+			t.Errorf("item %d (%s) = %+v, wantErr %v", i, item.TokenSymbol, item, wantErrs[i])
+		}
+	}
+}
+
+func TestGetTokenRecoverApprovalBatchRequest_Validate(t *testing.T) {
+	var req GetTokenRecoverApprovalBatchRequest
+	body := `{"owner_pub_key": "0x036d5d41cd7da2e96d39bcbd0390bfed461a86382f7a2923436ff16c65cabc7719", "claim_address": "0x2e9247B67ae885a8dcfBf77Eb6d0e93A32bea24C", "items": [null]}`
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatal(err)
+	}
+	if err := req.Validate(); err == nil {
+		t.Error("GetTokenRecoverApprovalBatchRequest.Validate() accepts a null item")
+	}
+}
+
+func TestDigester(t *testing.T) {
+	eip712Config := config.EIP712Config{
+		Name:              "TokenRecoverPortal",
+		Version:           "1",
+		ChainID:           97,
+		VerifyingContract: "0x0000000000000000000000000000000000003000",
+	}
+	payload := &ApprovalPayload{
+		ChainID:        "Binance-Chain-Ganges",
+		ClaimAddress:   common.HexToAddress("0x2e9247B67ae885a8dcfBf77Eb6d0e93A32bea24C"),
+		OwnerSignature: util.MustDecodeHexToBytes("0x5f5391ba7f2b002b4746025f7e803a43e57a397ea66f3939d05302eb7851bbbc0773cda87aae0fbb1e2a29367b606209ed47dc5cba6d1a83f6b79cb70e56efdb"),
+		Leaf:           util.MustDecodeHexToBytes("0x6e52674467a2c71219dd6b2922f670eeda81081b94c8c7a19ac994b4923a3a3e"),
+		MerkleRoot:     util.MustDecodeHexToBytes(mockMerkleRoot),
+		Proof: util.MustDecodeHexArrayToBytes([]string{
+			"0x03719d7863e4aba727d7030e7a1916b9be2245d447eb71fc683d3ac0ded5eecd",
+			"0x7f9aa9d8246251cbab3cc642416dec81d074d39a85be6ca8326a05ac422e74ab",
+		}),
+	}
+	tests := []struct {
+		name          string
+		scheme        SignScheme
+		deadline      uint64
+		wantDigest    string
+		wantSignature string
+	}{
+		{
+			name:          "legacy",
+			scheme:        LegacySignScheme,
+			wantDigest:    "0x8dcacb3fdb21b813694bded85ef1e7b4b2165d75678a3da1c454bf4553f7ec1f",
+			wantSignature: "0x1ffd72e2052b8369c9786384ac936c2d39c398b32d45f3b5697582871bf60eb7305c9aa2a6275186888f03109bdcbd6066a2f61b9b80061e1e32ff588bd5211500",
+		},
+		{
+			name:          "eip712",
+			scheme:        EIP712SignScheme,
+			wantDigest:    "0x6fdf8b27de7374f4216dd2ef7519431092c80e244bc28857ca06a4f17a6c1b06",
+			wantSignature: "0x75ffdb22484776c6db6170b9b83d093edecbcb01aabb126b436dcc7275a654a17dc682722473b1ce9701fba8da4f0eeef102494a40039d4ae79be89c7c99af0401",
+		},
+		{
+			name:          "legacy with deadline",
+			scheme:        LegacySignScheme,
+			deadline:      1735689600,
+			wantDigest:    "0x447bcba75d30ee0fa8c2087656f273f6c8d13ad8088334974740393b9443e281",
+			wantSignature: "0x4fe8cecc6e9c7f0ab2ef293b0ef85a6ead679f2753d54857e87d63487b312b6756cc6ed28e006305ad3b2c19d7cddd59ed45b9f9a96686795d4731c9e82a41e300",
+		},
+		{
+			name:          "eip712 with deadline",
+			scheme:        EIP712SignScheme,
+			deadline:      1735689600,
+			wantDigest:    "0x21224c673664fe04f5add7b8e29f2dfed29cfc409cea47a5011d407cfedf198b",
+			wantSignature: "0x2ad230b7d7ba593effe1a2eb315315deaf2895d10b9938a8e33283e69743999f2291dafa06b56f773b0a7c0f1b3af7e05cd2a2989cc1390f2dd59884cd727aa001",
+		},
+	}
+	km, err := local.NewLocalKeyManager(approvalPrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digester, err := NewDigester(&config.ApprovalConfig{SignScheme: string(tt.scheme), EIP712: eip712Config})
+			if err != nil {
+				t.Fatal(err)
+			}
+			payload.Deadline = tt.deadline
+			digest := digester.Digest(payload)
+			if hexutil.Encode(digest) != tt.wantDigest {
+				t.Errorf("Digest() = %s, want %s", hexutil.Encode(digest), tt.wantDigest)
+			}
+			signature, err := km.Sign(digest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hexutil.Encode(signature) != tt.wantSignature {
+				t.Errorf("Sign() = %s, want %s", hexutil.Encode(signature), tt.wantSignature)
+			}
+		})
+	}
+}
+
+func TestApprovalService_MultiSigner(t *testing.T) {
+	privKeys := []string{
+		approvalPrivKey,
+		"4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+		"8da4ef21b864d2cc526dbdb2a120bd2874c36c9d0a1fb7f8c63d7f7a8b41de8f",
+	}
+	svc, err := makeMockSvc(privKeys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &GetTokenRecoverApprovalRequest{
+		TokenSymbol:    "BNB",
+		OwnerPubKey:    "0x036d5d41cd7da2e96d39bcbd0390bfed461a86382f7a2923436ff16c65cabc7719",
+		OwnerSignature: "0x5f5391ba7f2b002b4746025f7e803a43e57a397ea66f3939d05302eb7851bbbc0773cda87aae0fbb1e2a29367b606209ed47dc5cba6d1a83f6b79cb70e56efdb",
+		ClaimAddress:   common.HexToAddress("0x2e9247B67ae885a8dcfBf77Eb6d0e93A32bea24C"),
+	}
+	resp, err := svc.GetTokenRecoverApproval(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.ApprovalSignatures) != len(privKeys) || len(resp.Signers) != len(privKeys) {
+		t.Fatalf("got %d signatures of %d signers, want %d", len(resp.ApprovalSignatures), len(resp.Signers), len(privKeys))
+	}
+	if !reflect.DeepEqual(resp.Signers, svc.signer.Addresses()) {
+		t.Errorf("Signers = %v, want %v", resp.Signers, svc.signer.Addresses())
+	}
+	if !reflect.DeepEqual(resp.ApprovalSignature, resp.ApprovalSignatures[0]) {
+		t.Errorf("ApprovalSignature = %x, want the first signature %x", resp.ApprovalSignature, resp.ApprovalSignatures[0])
+	}
+
+	ownerAddr, err := svc.getAddressFromPubKey(util.MustDecodeHexToBytes(req.OwnerPubKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafBytes, err := (&store.Proof{Address: ownerAddr, Denom: req.TokenSymbol, Amount: resp.Amount.Int64()}).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := svc.digester.Digest(&ApprovalPayload{
+		ChainID:        svc.config.ChainID,
+		ClaimAddress:   req.ClaimAddress,
+		OwnerSignature: util.MustDecodeHexToBytes(req.OwnerSignature),
+		Leaf:           leafBytes,
+		MerkleRoot:     svc.merkleRoot,
+		Proof:          resp.Proofs,
+	})
+	for i, signature := range resp.ApprovalSignatures {
+		if !svc.signer.Verify(resp.Signers[i], digest, signature) {
+			t.Errorf("signature %d is not signed by %s", i, resp.Signers[i])
+		}
+	}
+}
+
+func TestApprovalService_RotateSigners(t *testing.T) {
+	const nextPrivKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	svc, err := makeMockSvc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	active, _ := local.NewLocalKeyManager(approvalPrivKey)
+	next, _ := local.NewLocalKeyManager(nextPrivKey)
+	svc.signer, err = keymanager.NewThresholdSigner(1, keymanager.NewRotatingKeyManager(active, next, time.Time{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &GetTokenRecoverApprovalRequest{
+		TokenSymbol:    "BNB",
+		OwnerPubKey:    "0x036d5d41cd7da2e96d39bcbd0390bfed461a86382f7a2923436ff16c65cabc7719",
+		OwnerSignature: "0x5f5391ba7f2b002b4746025f7e803a43e57a397ea66f3939d05302eb7851bbbc0773cda87aae0fbb1e2a29367b606209ed47dc5cba6d1a83f6b79cb70e56efdb",
+		ClaimAddress:   common.HexToAddress("0x2e9247B67ae885a8dcfBf77Eb6d0e93A32bea24C"),
+	}
+	resp, err := svc.GetTokenRecoverApproval(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Signers[0] != active.Address() {
+		t.Fatalf("expected signer %s before rotation, got %s", active.Address(), resp.Signers[0])
+	}
+
+	rotations, err := svc.RotateSigners()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotations) != 1 || rotations[0].Active != next.Address() || rotations[0].Next != nil {
+		t.Fatalf("unexpected rotations %+v", rotations)
+	}
+	// the approval signed by the previous key is re-issued by the active key
+	resp, err = svc.GetTokenRecoverApproval(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Signers[0] != next.Address() {
+		t.Fatalf("expected signer %s after rotation, got %s", next.Address(), resp.Signers[0])
+	}
+	if _, err := svc.RotateSigners(); !errors.Is(err, keymanager.ErrNoNextKey) {
+		t.Fatalf("expected ErrNoNextKey, got %v", err)
+	}
+
+	// the next key is activated once the activation time is reached
+	scheduled := keymanager.NewRotatingKeyManager(active, next, time.Now().Add(-time.Second))
+	if scheduled.Address() != next.Address() {
+		t.Fatalf("expected scheduled rotation to %s, got %s", next.Address(), scheduled.Address())
+	}
+	pending := keymanager.NewRotatingKeyManager(active, next, time.Now().Add(time.Hour))
+	if rotation := pending.Rotation(); rotation.Active != active.Address() || rotation.Next == nil || *rotation.Next != next.Address() {
+		t.Fatalf("unexpected pending rotation %+v", rotation)
+	}
+}
+
+func TestApprovalService_RotateSigners_State(t *testing.T) {
+	const nextPrivKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	svc, err := makeMockSvc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	active, _ := local.NewLocalKeyManager(approvalPrivKey)
+	next, _ := local.NewLocalKeyManager(nextPrivKey)
+	svc.config.Secret.RotationStateFile = filepath.Join(t.TempDir(), "rotation.json")
+	svc.signer, err = keymanager.NewThresholdSigner(1, keymanager.NewRotatingKeyManager(active, next, time.Time{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RotateSigners(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a restarted signer activates the saved next key again
+	rotations, err := keymanager.LoadRotations(svc.config.Secret.RotationStateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted, err := keymanager.NewThresholdSigner(1, keymanager.NewRotatingKeyManager(active, next, time.Time{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restarted.Restore(rotations); err != nil {
+		t.Fatal(err)
+	}
+	if addresses := restarted.Addresses(); addresses[0] != next.Address() {
+		t.Fatalf("expected restored signer %s, got %s", next.Address(), addresses[0])
+	}
+
+	// a next key must not be a key of another signer
+	other, _ := local.NewLocalKeyManager(nextPrivKey)
+	if _, err := keymanager.NewThresholdSigner(1, keymanager.NewRotatingKeyManager(active, next, time.Time{}), other); err == nil {
+		t.Fatal("expected an error for a next key which is already a signer")
+	}
+}
+
+func TestRecoverApprovalSigners(t *testing.T) {
+	svc, err := makeMockSvc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &GetTokenRecoverApprovalRequest{
+		TokenSymbol:    "BNB",
+		OwnerPubKey:    "0x036d5d41cd7da2e96d39bcbd0390bfed461a86382f7a2923436ff16c65cabc7719",
+		OwnerSignature: "0x5f5391ba7f2b002b4746025f7e803a43e57a397ea66f3939d05302eb7851bbbc0773cda87aae0fbb1e2a29367b606209ed47dc5cba6d1a83f6b79cb70e56efdb",
+		ClaimAddress:   common.HexToAddress("0x2e9247B67ae885a8dcfBf77Eb6d0e93A32bea24C"),
+	}
+	resp, err := svc.GetTokenRecoverApproval(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signers, err := RecoverApprovalSigners(svc.config, req, resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signers) != 1 || signers[0] != common.HexToAddress(approvalAddress) {
+		t.Fatalf("RecoverApprovalSigners() = %v, want [%s]", signers, approvalAddress)
+	}
+
+	tampered := *req
+	tampered.ClaimAddress = common.HexToAddress("0x2e9247B67ae885a8dcfBf77Eb6d0e93A32bea24D")
+	if _, err := RecoverApprovalSigners(svc.config, &tampered, resp); err == nil {
+		t.Fatal("expected an error for a tampered claim address")
+	}
+}
+
+func TestTokenRecoverRequestMsgBytes(t *testing.T) {
+	ownerPubKey := util.MustDecodeHexToBytes("0x036d5d41cd7da2e96d39bcbd0390bfed461a86382f7a2923436ff16c65cabc7719")
+	ownerSignature := util.MustDecodeHexToBytes("0x5f5391ba7f2b002b4746025f7e803a43e57a397ea66f3939d05302eb7851bbbc0773cda87aae0fbb1e2a29367b606209ed47dc5cba6d1a83f6b79cb70e56efdb")
+	claimAddress := common.HexToAddress("0x2e9247B67ae885a8dcfBf77Eb6d0e93A32bea24C")
+
+	msgBytes, err := TokenRecoverRequestMsgBytes("Binance-Chain-Ganges", "BNB", 14188000000, claimAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !secp256k1.PubKeySecp256k1(ownerPubKey).VerifyBytes(msgBytes, ownerSignature) {
+		t.Fatalf("owner signature doesn't verify the msg bytes %s", msgBytes)
+	}
+	// the owner signs over the amount and the claim address
+	msgBytes, err = TokenRecoverRequestMsgBytes("Binance-Chain-Ganges", "BNB", 14188000001, claimAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secp256k1.PubKeySecp256k1(ownerPubKey).VerifyBytes(msgBytes, ownerSignature) {
+		t.Fatal("owner signature verifies a different amount")
+	}
+}
+
+func TestApprovalService_CheckIntegrity(t *testing.T) {
+	svc, err := makeMockSvc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := svc.CheckIntegrity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Checked != 2 || result.Mismatched != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	// every proof mismatches another root
+	svc.merkleRoot = util.MustDecodeHexToBytes("0x432fab19dadaf4edef24af40dc5c780a8c9c72906922b5558bdb9a1d68dc5d09")
+	if _, err := svc.CheckIntegrity(); !errors.Is(err, ErrIntegrityCheck) {
+		t.Fatalf("expected ErrIntegrityCheck, got %v", err)
+	}
+	svc.config.Approval.IntegrityCheck.MaxMismatches = 2
+	result, err = svc.CheckIntegrity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Checked != 2 || result.Mismatched != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestNewApprovalService_DeadlineTTL(t *testing.T) {
+	svc, err := makeMockSvc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ttl := range []time.Duration{0, -time.Hour} {
+		_, err := NewApprovalService(&config.Config{
+			ChainID:    "Binance-Chain-Ganges",
+			MerkleRoot: mockMerkleRoot,
+			Approval: config.ApprovalConfig{
+				Deadline: config.DeadlineConfig{Enable: true, TTL: ttl},
+			},
+		}, svc.signer, svc.store, svc.approvalStore, collector.NewCollector(prometheus.NewRegistry()), &zerolog.Logger{})
+		if err == nil {
+			t.Errorf("NewApprovalService() accepts deadline ttl %s", ttl)
+		}
+	}
+}
+
+func TestApprovalService_GetAccountAssets(t *testing.T) {
+	svc, err := makeMockSvc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err := types.AccAddressFromBech32("tbnb1kufjqrefala5ylahdgv9kjk886sf556tdcs2n5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := svc.GetAccountAssets(owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Asset{
+		{Denom: "BNB", Amount: big.NewInt(14188000000)},
+		{Denom: "DYTT991-49A", Amount: big.NewInt(10000000000000000)},
+	}
+	if !resp.Address.Equals(owner) || !reflect.DeepEqual(resp.Assets, want) {
+		t.Fatalf("GetAccountAssets() = %+v", resp)
+	}
+
+	// an account without proofs has no assets
+	resp, err = svc.GetAccountAssets(types.AccAddress(common.HexToAddress(approvalAddress).Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Assets) != 0 {
+		t.Fatalf("GetAccountAssets() of an unknown account = %+v", resp.Assets)
+	}
+}
+
+func TestApprovalService_GetAccountAssetProof(t *testing.T) {
+	svc, err := makeMockSvc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err := types.AccAddressFromBech32("tbnb1kufjqrefala5ylahdgv9kjk886sf556tdcs2n5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, asset := range []*Asset{
+		{Denom: "BNB", Amount: big.NewInt(14188000000)},
+		{Denom: "DYTT991-49A", Amount: big.NewInt(10000000000000000)},
+	} {
+		resp, err := svc.GetAccountAssetProof(owner, asset.Denom)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.Address.Equals(owner) || resp.Denom != asset.Denom || resp.Amount.Cmp(asset.Amount) != 0 {
+			t.Fatalf("GetAccountAssetProof(%s) = %+v", asset.Denom, resp)
+		}
+		// the leaf and proofs verify against the merkle root like the BSC contract does
+		if !util.VerifyMerkleProof(svc.merkleRoot, resp.Proofs, resp.Leaf) {
+			t.Fatalf("GetAccountAssetProof(%s) leaf does not verify against the merkle root", asset.Denom)
+		}
+	}
+
+	if _, err := svc.GetAccountAssetProof(owner, "XYZ-456"); !errors.Is(err, store.ErrProofNotFound) {
+		t.Fatalf("GetAccountAssetProof() of an unknown asset error = %v", err)
+	}
+}
+
+func TestApprovalService_GetServiceInfo(t *testing.T) {
+	svc, err := makeMockSvc(approvalPrivKey, "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.config.Store.Driver = "memory"
+	appVersion, gitCommit, gitCommitDate := version.AppVersion, version.GitCommit, version.GitCommitDate
+	version.AppVersion, version.GitCommit, version.GitCommitDate = "v1.2.3", "0123abc", "2024-04-01"
+	defer func() {
+		version.AppVersion, version.GitCommit, version.GitCommitDate = appVersion, gitCommit, gitCommitDate
+	}()
+
+	info, err := svc.GetServiceInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signers := []common.Address{common.HexToAddress(approvalAddress), common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")}
+	want := &GetServiceInfoResponse{
+		ApproverAddress:   signers[0],
+		ApproverAddresses: signers,
+		Threshold:         2,
+		Rotations:         []keymanager.Rotation{{Active: signers[0]}, {Active: signers[1]}},
+		MerkleRoot:        mockMerkleRoot,
+		ChainID:           "Binance-Chain-Ganges",
+		StoreDriver:       "memory",
+		ProofCount:        2,
+		Version:           &VersionInfo{AppVersion: "v1.2.3", GitCommit: "0123abc", GitCommitDate: "2024-04-01"},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Fatalf("GetServiceInfo() = %+v, want %+v", info, want)
+	}
+}
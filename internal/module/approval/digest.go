@@ -0,0 +1,121 @@
+package approval
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bnb-chain/token-recover-approver/internal/config"
+)
+
+// SignScheme is the scheme used to compute the digest signed by the approver
+type SignScheme string
+
+const (
+	// LegacySignScheme signs the keccak256 hash of the concatenated payload fields
+	LegacySignScheme SignScheme = "legacy"
+	// EIP712SignScheme signs the EIP-712 typed data hash of the payload
+	EIP712SignScheme SignScheme = "eip712"
+)
+
+const (
+	eip712DomainType         = "EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"
+	tokenRecoverApprovalType = "TokenRecoverApproval(string sourceChainId,address claimAddress,bytes ownerSignature,bytes32 leaf,bytes32 merkleRoot,bytes32[] proof)"
+	// the deadline is appended to the signed data only if it is set
+	tokenRecoverApprovalWithDeadlineType = "TokenRecoverApproval(string sourceChainId,address claimAddress,bytes ownerSignature,bytes32 leaf,bytes32 merkleRoot,bytes32[] proof,uint256 deadline)"
+)
+
+// ApprovalPayload is the data covered by the approval signature
+type ApprovalPayload struct {
+	ChainID        string
+	ClaimAddress   common.Address
+	OwnerSignature []byte
+	Leaf           []byte
+	MerkleRoot     []byte
+	Proof          [][]byte
+	Deadline       uint64 // unix seconds, zero means no deadline
+}
+
+// Digester computes the digest of an approval payload
+type Digester interface {
+	Digest(payload *ApprovalPayload) []byte
+}
+
+// NewDigester returns the Digester of the configured sign scheme.
+func NewDigester(config *config.ApprovalConfig) (Digester, error) {
+	switch SignScheme(config.SignScheme) {
+	case LegacySignScheme, "":
+		return legacyDigester{}, nil
+	case EIP712SignScheme:
+		if !common.IsHexAddress(config.EIP712.VerifyingContract) {
+			return nil, fmt.Errorf("invalid eip712 verifying contract: %s", config.EIP712.VerifyingContract)
+		}
+		return newEIP712Digester(
+			config.EIP712.Name,
+			config.EIP712.Version,
+			config.EIP712.ChainID,
+			common.HexToAddress(config.EIP712.VerifyingContract),
+		), nil
+	default:
+		return nil, fmt.Errorf("unsupported sign scheme: %s", config.SignScheme)
+	}
+}
+
+type legacyDigester struct{}
+
+// Digest implements Digester.
+func (legacyDigester) Digest(payload *ApprovalPayload) []byte {
+	signData := make([][]byte, 0, len(payload.Proof)+6)
+	signData = append(signData, [][]byte{
+		[]byte(payload.ChainID), payload.ClaimAddress[:], payload.OwnerSignature, payload.Leaf,
+		payload.MerkleRoot,
+	}...)
+	if payload.Deadline != 0 {
+		signData = append(signData, uint256Bytes(payload.Deadline))
+	}
+	signData = append(signData, payload.Proof...)
+	return crypto.Keccak256(signData...)
+}
+
+type eip712Digester struct {
+	domainSeparator []byte
+}
+
+func newEIP712Digester(name, version string, chainID uint64, verifyingContract common.Address) eip712Digester {
+	domainSeparator := crypto.Keccak256(
+		crypto.Keccak256([]byte(eip712DomainType)),
+		crypto.Keccak256([]byte(name)),
+		crypto.Keccak256([]byte(version)),
+		uint256Bytes(chainID),
+		common.BytesToHash(verifyingContract[:]).Bytes(),
+	)
+	return eip712Digester{domainSeparator: domainSeparator}
+}
+
+// Digest implements Digester.
+func (d eip712Digester) Digest(payload *ApprovalPayload) []byte {
+	typeHash := crypto.Keccak256([]byte(tokenRecoverApprovalType))
+	if payload.Deadline != 0 {
+		typeHash = crypto.Keccak256([]byte(tokenRecoverApprovalWithDeadlineType))
+	}
+	encodedData := [][]byte{
+		typeHash,
+		crypto.Keccak256([]byte(payload.ChainID)),
+		common.BytesToHash(payload.ClaimAddress[:]).Bytes(),
+		crypto.Keccak256(payload.OwnerSignature),
+		common.BytesToHash(payload.Leaf).Bytes(),
+		common.BytesToHash(payload.MerkleRoot).Bytes(),
+		crypto.Keccak256(payload.Proof...),
+	}
+	if payload.Deadline != 0 {
+		encodedData = append(encodedData, uint256Bytes(payload.Deadline))
+	}
+	structHash := crypto.Keccak256(encodedData...)
+	return crypto.Keccak256([]byte{0x19, 0x01}, d.domainSeparator, structHash)
+}
+
+func uint256Bytes(v uint64) []byte {
+	return common.BigToHash(new(big.Int).SetUint64(v)).Bytes()
+}
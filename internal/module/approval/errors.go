@@ -0,0 +1,18 @@
+package approval
+
+import "errors"
+
+var (
+	// ErrInvalidRequest is returned when the request can not be decoded
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrNotWhitelisted is returned when the owner address is not in the account white list
+	ErrNotWhitelisted = errors.New("address is not in white list")
+	// ErrZeroAmount is returned when the token amount in the snapshot is zero
+	ErrZeroAmount = errors.New("token amount is zero")
+	// ErrInvalidOwnerSignature is returned when the owner signature does not match the request
+	ErrInvalidOwnerSignature = errors.New("verify owner signature failed")
+	// ErrInvalidMerkleProof is returned when the proof in store does not match the merkle root
+	ErrInvalidMerkleProof = errors.New("verify merkle proof failed")
+	// ErrDoubleClaim is returned when the token has been approved for another claim address
+	ErrDoubleClaim = errors.New("token has been approved for another claim address")
+)
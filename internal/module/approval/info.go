@@ -0,0 +1,33 @@
+package approval
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/bnb-chain/token-recover-approver/internal/version"
+)
+
+// GetServiceInfo returns the approver address, merkle root and other values
+// which can be checked against the BSC system contract.
+func (svc *ApprovalService) GetServiceInfo() (*GetServiceInfoResponse, error) {
+	proofCount, err := svc.store.CountAccountAssetProofs()
+	if err != nil {
+		return nil, err
+	}
+
+	approverAddresses := svc.signer.Addresses()
+	return &GetServiceInfoResponse{
+		ApproverAddress:   approverAddresses[0],
+		ApproverAddresses: approverAddresses,
+		Threshold:         svc.signer.Threshold(),
+		Rotations:         svc.signer.Rotations(),
+		MerkleRoot:        hexutil.Encode(svc.merkleRoot),
+		ChainID:           svc.config.ChainID,
+		StoreDriver:       svc.config.Store.Driver,
+		ProofCount:        proofCount,
+		Version: &VersionInfo{
+			AppVersion:    version.AppVersion,
+			GitCommit:     version.GitCommit,
+			GitCommitDate: version.GitCommitDate,
+		},
+	}, nil
+}
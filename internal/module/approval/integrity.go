@@ -0,0 +1,61 @@
+package approval
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+	"github.com/bnb-chain/token-recover-approver/pkg/util"
+)
+
+// ErrIntegrityCheck is returned when too many proofs in the store don't match the merkle root.
+var ErrIntegrityCheck = errors.New("store integrity check failed")
+
+// IntegrityCheckResult counts the proofs checked against the merkle root.
+type IntegrityCheckResult struct {
+	Checked    int64
+	Mismatched int64
+}
+
+// CheckIntegrity verifies every proof in the store against the merkle root,
+// it stops once more than approval.integrity_check.max_mismatches proofs don't match.
+func (svc *ApprovalService) CheckIntegrity() (*IntegrityCheckResult, error) {
+	checkConfig := svc.config.Approval.IntegrityCheck
+	startTime := time.Now()
+	result := &IntegrityCheckResult{}
+	svc.logger.Info().Str("merkle_root", hexutil.Encode(svc.merkleRoot)).Int64("max_mismatches", checkConfig.MaxMismatches).Msg("integrity check started")
+
+	err := svc.store.Iterate(func(proof *store.Proof) error {
+		result.Checked++
+		if !svc.verifyStoredProof(proof) {
+			result.Mismatched++
+			svc.logger.Warn().Str("address", proof.Address.String()).Str("symbol", proof.Denom).Int64("amount", proof.Amount).Msg("proof doesn't match the merkle root")
+			if result.Mismatched > checkConfig.MaxMismatches {
+				return fmt.Errorf("%w: %d mismatched proofs of %d checked", ErrIntegrityCheck, result.Mismatched, result.Checked)
+			}
+		}
+		if checkConfig.ProgressInterval > 0 && result.Checked%checkConfig.ProgressInterval == 0 {
+			svc.metrics.SetIntegrityCheckProgress(result.Checked, result.Mismatched)
+			svc.logger.Info().Int64("checked", result.Checked).Int64("mismatched", result.Mismatched).Msg("integrity check progress")
+		}
+		return nil
+	})
+	svc.metrics.SetIntegrityCheckProgress(result.Checked, result.Mismatched)
+	if err != nil {
+		return result, err
+	}
+
+	svc.logger.Info().Int64("checked", result.Checked).Int64("mismatched", result.Mismatched).Dur("duration", time.Since(startTime)).Msg("integrity check finished")
+	return result, nil
+}
+
+func (svc *ApprovalService) verifyStoredProof(proof *store.Proof) bool {
+	leaf, err := proof.Serialize()
+	if err != nil {
+		return false
+	}
+	return util.VerifyMerkleProof(svc.merkleRoot, proof.Proof, leaf)
+}
@@ -0,0 +1,34 @@
+package approval
+
+import (
+	"fmt"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
+)
+
+// RotateSigners activates the next key of every rotating signer without a restart.
+func (svc *ApprovalService) RotateSigners() ([]keymanager.Rotation, error) {
+	rotations, err := svc.signer.Rotate()
+	if err != nil {
+		return nil, err
+	}
+	svc.logger.Info().Interface("rotations", rotations).Msg("approver signers rotated")
+	svc.updateActiveSigners()
+	if path := svc.config.Secret.RotationStateFile; path != "" {
+		if err := keymanager.SaveRotations(path, rotations); err != nil {
+			svc.logger.Error().Err(err).Str("path", path).Msg("failed to save rotation state")
+			return nil, fmt.Errorf("signers rotated but the rotation state is not saved: %w", err)
+		}
+	}
+	return rotations, nil
+}
+
+// updateActiveSigners reports the active signer addresses, which change once a next key is activated.
+func (svc *ApprovalService) updateActiveSigners() {
+	addresses := svc.signer.Addresses()
+	activeSigners := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		activeSigners = append(activeSigners, address.Hex())
+	}
+	svc.metrics.SetActiveSigners(activeSigners)
+}
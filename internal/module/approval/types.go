@@ -6,7 +6,10 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
 	"github.com/bnb-chain/token-recover-approver/pkg/util"
+	"github.com/cosmos/cosmos-sdk/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
@@ -14,8 +17,28 @@ import (
 const (
 	PubKeyLength    = 33
 	SignatureLength = 64
+	MaxBatchSize    = 100
 )
 
+// LedgerPolicy decides how a request for an already approved token is handled
+type LedgerPolicy string
+
+const (
+	// ReissuePolicy always signs a new approval
+	ReissuePolicy LedgerPolicy = "reissue"
+	// ReplayPolicy returns the issued approval for the same claim address
+	ReplayPolicy LedgerPolicy = "replay"
+	// RejectPolicy returns the issued approval for the same claim address,
+	// and rejects the request if the token has been approved for another claim address
+	RejectPolicy LedgerPolicy = "reject"
+)
+
+var supportedLedgerPolicies = map[LedgerPolicy]struct{}{
+	ReissuePolicy: {},
+	ReplayPolicy:  {},
+	RejectPolicy:  {},
+}
+
 type GetTokenRecoverApprovalRequest struct {
 	TokenSymbol    string         `json:"token_symbol" validate:"required"`
 	OwnerPubKey    string         `json:"owner_pub_key" validate:"required"`
@@ -25,45 +48,236 @@ type GetTokenRecoverApprovalRequest struct {
 
 func (req *GetTokenRecoverApprovalRequest) Validate() error {
 	if (req.ClaimAddress == common.Address{}) {
-		return errors.New("claim address is empty")
+		return errors.Wrap(ErrInvalidRequest, "claim address is empty")
 	}
 
 	pubKey, err := hexutil.Decode(req.OwnerPubKey)
 	if err != nil {
-		return errors.Wrap(err, "decode owner public key")
+		return errors.Wrapf(ErrInvalidRequest, "decode owner public key: %v", err)
 	}
 
 	if len(pubKey) != PubKeyLength {
-		return errors.New("invalid owner public key")
+		return errors.Wrap(ErrInvalidRequest, "invalid owner public key")
 	}
 
 	signature, err := hexutil.Decode(req.OwnerSignature)
 	if err != nil {
-		return errors.Wrap(err, "decode owner signature")
+		return errors.Wrapf(ErrInvalidRequest, "decode owner signature: %v", err)
 	}
 
 	if len(signature) != SignatureLength {
-		return errors.New("invalid owner signature")
+		return errors.Wrap(ErrInvalidRequest, "invalid owner signature")
 	}
 
 	return nil
 }
 
 type GetTokenRecoverApprovalResponse struct {
-	Amount            *big.Int `json:"amount"`
-	Proofs            [][]byte `json:"proofs"`
-	ApprovalSignature []byte   `json:"approval_signature"`
+	Amount   *big.Int `json:"amount"`
+	Proofs   [][]byte `json:"proofs"`
+	Deadline uint64   `json:"deadline,omitempty"` // unix seconds
+	// ApprovalSignature is the signature of the first signer, it is kept for single signer clients
+	ApprovalSignature  []byte           `json:"approval_signature"`
+	ApprovalSignatures [][]byte         `json:"approval_signatures"`
+	Signers            []common.Address `json:"signers"`
+}
+
+func newGetTokenRecoverApprovalResponse(proof *store.Proof, signatures [][]byte, signers []common.Address, deadline uint64) *GetTokenRecoverApprovalResponse {
+	return &GetTokenRecoverApprovalResponse{
+		Amount:             big.NewInt(proof.Amount),
+		Proofs:             proof.Proof,
+		Deadline:           deadline,
+		ApprovalSignature:  signatures[0],
+		ApprovalSignatures: signatures,
+		Signers:            signers,
+	}
 }
 
 func (resp *GetTokenRecoverApprovalResponse) MarshalJSON() ([]byte, error) {
 	type aliasGetTokenRecoverApprovalResponse struct {
-		Amount            *big.Int `json:"amount"`
-		Proofs            []string `json:"proofs"`
-		ApprovalSignature string   `json:"approval_signature"`
+		Amount             *big.Int         `json:"amount"`
+		Proofs             []string         `json:"proofs"`
+		Deadline           uint64           `json:"deadline,omitempty"`
+		ApprovalSignature  string           `json:"approval_signature"`
+		ApprovalSignatures []string         `json:"approval_signatures"`
+		Signers            []common.Address `json:"signers"`
 	}
 	return json.Marshal(&aliasGetTokenRecoverApprovalResponse{
-		Amount:            resp.Amount,
-		Proofs:            util.EncodeBytesArrayToHex(resp.Proofs),
-		ApprovalSignature: hexutil.Encode(resp.ApprovalSignature),
+		Amount:             resp.Amount,
+		Proofs:             util.EncodeBytesArrayToHex(resp.Proofs),
+		Deadline:           resp.Deadline,
+		ApprovalSignature:  hexutil.Encode(resp.ApprovalSignature),
+		ApprovalSignatures: util.EncodeBytesArrayToHex(resp.ApprovalSignatures),
+		Signers:            resp.Signers,
+	})
+}
+
+func (resp *GetTokenRecoverApprovalResponse) UnmarshalJSON(data []byte) error {
+	type aliasGetTokenRecoverApprovalResponse struct {
+		Amount             *big.Int         `json:"amount"`
+		Proofs             []string         `json:"proofs"`
+		Deadline           uint64           `json:"deadline,omitempty"`
+		ApprovalSignature  string           `json:"approval_signature"`
+		ApprovalSignatures []string         `json:"approval_signatures"`
+		Signers            []common.Address `json:"signers"`
+	}
+	var alias aliasGetTokenRecoverApprovalResponse
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+	proofs, err := decodeHexArray(alias.Proofs)
+	if err != nil {
+		return errors.Wrap(err, "decode proofs")
+	}
+	approvalSignature, err := hexutil.Decode(alias.ApprovalSignature)
+	if err != nil {
+		return errors.Wrap(err, "decode approval signature")
+	}
+	approvalSignatures, err := decodeHexArray(alias.ApprovalSignatures)
+	if err != nil {
+		return errors.Wrap(err, "decode approval signatures")
+	}
+	// approvals of single signer versions only have approval_signature
+	if len(approvalSignatures) == 0 {
+		approvalSignatures = [][]byte{approvalSignature}
+	}
+	*resp = GetTokenRecoverApprovalResponse{
+		Amount:             alias.Amount,
+		Proofs:             proofs,
+		Deadline:           alias.Deadline,
+		ApprovalSignature:  approvalSignature,
+		ApprovalSignatures: approvalSignatures,
+		Signers:            alias.Signers,
+	}
+	return nil
+}
+
+func decodeHexArray(hexArray []string) ([][]byte, error) {
+	data := make([][]byte, 0, len(hexArray))
+	for _, h := range hexArray {
+		b, err := hexutil.Decode(h)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b)
+	}
+	return data, nil
+}
+
+type GetTokenRecoverApprovalBatchRequest struct {
+	OwnerPubKey  string                      `json:"owner_pub_key" validate:"required"`
+	ClaimAddress common.Address              `json:"claim_address" validate:"required"`
+	Items        []*TokenRecoverApprovalItem `json:"items" validate:"required,dive,required"`
+}
+
+type TokenRecoverApprovalItem struct {
+	TokenSymbol    string `json:"token_symbol" validate:"required"`
+	OwnerSignature string `json:"owner_signature" validate:"required"`
+}
+
+// Validate validates the fields shared by all items, the items are validated one by one when approving.
+func (req *GetTokenRecoverApprovalBatchRequest) Validate() error {
+	if (req.ClaimAddress == common.Address{}) {
+		return errors.New("claim address is empty")
+	}
+
+	pubKey, err := hexutil.Decode(req.OwnerPubKey)
+	if err != nil {
+		return errors.Wrap(err, "decode owner public key")
+	}
+
+	if len(pubKey) != PubKeyLength {
+		return errors.New("invalid owner public key")
+	}
+
+	if len(req.Items) == 0 {
+		return errors.New("items are empty")
+	}
+
+	if len(req.Items) > MaxBatchSize {
+		return errors.Errorf("too many items, max batch size is %d", MaxBatchSize)
+	}
+
+	for i, item := range req.Items {
+		if item == nil {
+			return errors.Errorf("item %d is empty", i)
+		}
+	}
+
+	return nil
+}
+
+// Request returns the single approval request of the item.
+func (req *GetTokenRecoverApprovalBatchRequest) Request(item *TokenRecoverApprovalItem) *GetTokenRecoverApprovalRequest {
+	return &GetTokenRecoverApprovalRequest{
+		TokenSymbol:    item.TokenSymbol,
+		OwnerPubKey:    req.OwnerPubKey,
+		OwnerSignature: item.OwnerSignature,
+		ClaimAddress:   req.ClaimAddress,
+	}
+}
+
+type GetTokenRecoverApprovalBatchResponse struct {
+	Items []*TokenRecoverApprovalResult `json:"items"`
+}
+
+// TokenRecoverApprovalResult is the outcome of an item, either Approval or Err is set.
+type TokenRecoverApprovalResult struct {
+	TokenSymbol string
+	Approval    *GetTokenRecoverApprovalResponse
+	Err         error
+}
+
+type GetAccountAssetsResponse struct {
+	Address types.AccAddress `json:"address"`
+	Assets  []*Asset         `json:"assets"`
+}
+
+type Asset struct {
+	Denom  string   `json:"denom"`
+	Amount *big.Int `json:"amount"`
+}
+
+type GetAccountAssetProofResponse struct {
+	Address types.AccAddress `json:"address"`
+	Denom   string           `json:"denom"`
+	Amount  *big.Int         `json:"amount"`
+	Leaf    []byte           `json:"leaf"`
+	Proofs  [][]byte         `json:"proofs"`
+}
+
+func (resp *GetAccountAssetProofResponse) MarshalJSON() ([]byte, error) {
+	type aliasGetAccountAssetProofResponse struct {
+		Address types.AccAddress `json:"address"`
+		Denom   string           `json:"denom"`
+		Amount  *big.Int         `json:"amount"`
+		Leaf    string           `json:"leaf"`
+		Proofs  []string         `json:"proofs"`
+	}
+	return json.Marshal(&aliasGetAccountAssetProofResponse{
+		Address: resp.Address,
+		Denom:   resp.Denom,
+		Amount:  resp.Amount,
+		Leaf:    hexutil.Encode(resp.Leaf),
+		Proofs:  util.EncodeBytesArrayToHex(resp.Proofs),
 	})
 }
+
+type GetServiceInfoResponse struct {
+	// ApproverAddress is the address of the first signer, it is kept for single signer clients
+	ApproverAddress   common.Address        `json:"approver_address"`
+	ApproverAddresses []common.Address      `json:"approver_addresses"`
+	Threshold         int                   `json:"threshold"`
+	Rotations         []keymanager.Rotation `json:"rotations"`
+	MerkleRoot        string                `json:"merkle_root"`
+	ChainID           string                `json:"chain_id"`
+	StoreDriver       string                `json:"store_driver"`
+	ProofCount        int64                 `json:"proof_count"`
+	Version           *VersionInfo          `json:"version"`
+}
+
+type VersionInfo struct {
+	AppVersion    string `json:"app_version"`
+	GitCommit     string `json:"git_commit"`
+	GitCommitDate string `json:"git_commit_date"`
+}
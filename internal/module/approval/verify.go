@@ -0,0 +1,75 @@
+package approval
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"github.com/bnb-chain/token-recover-approver/internal/config"
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+	"github.com/bnb-chain/token-recover-approver/pkg/util"
+)
+
+// RecoverApprovalSigners recomputes the digest signed for the approval of the request under the config,
+// and returns the address recovered from every approval signature.
+// The merkle proof of the approval is verified against the configured merkle root first.
+func RecoverApprovalSigners(config *config.Config, req *GetTokenRecoverApprovalRequest, resp *GetTokenRecoverApprovalResponse) ([]common.Address, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if resp.Amount == nil || !resp.Amount.IsInt64() {
+		return nil, fmt.Errorf("%w: invalid amount", ErrInvalidRequest)
+	}
+	merkleRoot, err := hexutil.Decode(config.MerkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	digester, err := NewDigester(&config.Approval)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerPubKey := hexutil.MustDecode(req.OwnerPubKey)
+	leaf := store.Proof{
+		Address: types.AccAddress(secp256k1.PubKeySecp256k1(ownerPubKey).Address()),
+		Denom:   req.TokenSymbol,
+		Amount:  resp.Amount.Int64(),
+	}
+	leafBytes, err := leaf.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	if !util.VerifyMerkleProof(merkleRoot, resp.Proofs, leafBytes) {
+		return nil, ErrInvalidMerkleProof
+	}
+
+	digest := digester.Digest(&ApprovalPayload{
+		ChainID:        config.ChainID,
+		ClaimAddress:   req.ClaimAddress,
+		OwnerSignature: hexutil.MustDecode(req.OwnerSignature),
+		Leaf:           leafBytes,
+		MerkleRoot:     merkleRoot,
+		Proof:          resp.Proofs,
+		Deadline:       resp.Deadline,
+	})
+	signers := make([]common.Address, 0, len(resp.ApprovalSignatures))
+	for i, signature := range resp.ApprovalSignatures {
+		if len(signature) != crypto.SignatureLength {
+			return nil, fmt.Errorf("invalid approval signature %d length %d", i, len(signature))
+		}
+		pubKey, err := crypto.SigToPub(digest, signature)
+		if err != nil {
+			return nil, fmt.Errorf("recover approval signature %d: %w", i, err)
+		}
+		signer := crypto.PubkeyToAddress(*pubKey)
+		if i < len(resp.Signers) && resp.Signers[i] != signer {
+			return nil, fmt.Errorf("approval signature %d is signed by %s, not by the claimed signer %s", i, signer, resp.Signers[i])
+		}
+		signers = append(signers, signer)
+	}
+	return signers, nil
+}
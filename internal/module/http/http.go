@@ -1,9 +1,13 @@
 package http
 
 import (
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/pprof"
+	"strings"
 
 	"github.com/felixge/fgprof"
 	"github.com/gorilla/context"
@@ -60,7 +64,7 @@ func (server *HttpServer) RunMetrics(config config.MetricsConfig) error {
 		IdleTimeout:       config.IdleTimeout,
 		MaxHeaderBytes:    config.MaxHeaderBytes,
 	}
-	server.setMetrics(router, config.Path, config.PProf)
+	server.setMetrics(router, config.Path, config.PProf, config.Admin, config.AdminToken)
 	return server.metricsServer.ListenAndServe()
 }
 
@@ -83,18 +87,33 @@ func (server *HttpServer) Shutdown() error {
 func (server *HttpServer) setRouter(router *httprouter.Router) {
 	server.logger.Info().Msg("http router list")
 	server.logger.Info().Msg("GET /ping")
+	server.logger.Info().Msg("GET /info")
 	server.logger.Info().Msg("POST /approve")
+	server.logger.Info().Msg("POST /approve/batch")
+	server.logger.Info().Msg("GET /accounts/:address/assets")
+	server.logger.Info().Msg("GET /accounts/:address/assets/:symbol/proof")
 
 	router.GET("/ping", server.Ping)
+	router.GET("/info", server.GetServiceInfo)
 	router.POST("/approve", server.GetTokenRecoverApproval)
+	router.POST("/approve/batch", server.GetTokenRecoverApprovalBatch)
+	router.GET("/accounts/:address/assets", server.GetAccountAssets)
+	router.GET("/accounts/:address/assets/:symbol/proof", server.GetAccountAssetProof)
 }
 
-func (server *HttpServer) setMetrics(router *httprouter.Router, path string, enablePProf bool) {
+func (server *HttpServer) setMetrics(router *httprouter.Router, path string, enablePProf bool, enableAdmin bool, adminToken string) {
 	server.logger.Info().Msg("metrics router list")
 	server.logger.Info().Msgf("GET %s", path)
 
 	router.GET(path, wrapHttpHandler(promhttp.HandlerFor(server.registry, promhttp.HandlerOpts{})))
 
+	if enableAdmin {
+		server.logger.Info().Msg("admin router list")
+		server.logger.Info().Msg("POST /admin/signers/rotate")
+
+		router.POST("/admin/signers/rotate", server.adminOnly(adminToken, server.RotateSigners))
+	}
+
 	if enablePProf {
 		server.logger.Info().Msg("pprof router list")
 		server.logger.Info().Msg("GET /debug/pprof/")
@@ -150,3 +169,28 @@ func wrapHttpHandleFunc(h http.HandlerFunc) httprouter.Handle {
 		h(rw, req)
 	}
 }
+
+// adminOnly serves the request if it carries the bearer token, or if no token is set, if it comes from a loopback address.
+func (server *HttpServer) adminOnly(token string, handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if !isAdmin(r, token) {
+			server.logger.Warn().Str("remote_addr", r.RemoteAddr).Str("path", r.URL.Path).Msg("unauthorized admin request")
+			server.Response(w, Unauthorized, nil, errors.New("unauthorized"))
+			return
+		}
+		handle(w, r, ps)
+	}
+}
+
+func isAdmin(r *http.Request, token string) bool {
+	if token != "" {
+		auth := r.Header.Get("Authorization")
+		return strings.HasPrefix(auth, "Bearer ") && subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) == 1
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/bnb-chain/token-recover-approver/internal/module/approval"
+	"github.com/cosmos/cosmos-sdk/types"
 	"github.com/julienschmidt/httprouter"
 )
 
@@ -14,6 +15,16 @@ func (server *HttpServer) Ping(w http.ResponseWriter, r *http.Request, _ httprou
 	server.Response(w, Success, "pong", nil)
 }
 
+func (server *HttpServer) GetServiceInfo(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	resp, err := server.approvalService.GetServiceInfo()
+	if err != nil {
+		server.Response(w, ErrorCode(err), nil, err)
+		return
+	}
+
+	server.Response(w, Success, resp, nil)
+}
+
 func (server *HttpServer) GetTokenRecoverApproval(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -36,10 +47,87 @@ func (server *HttpServer) GetTokenRecoverApproval(w http.ResponseWriter, r *http
 	}
 
 	resp, err := server.approvalService.GetTokenRecoverApproval(req)
+	if err != nil {
+		server.Response(w, ErrorCode(err), nil, err)
+		return
+	}
+
+	server.Response(w, Success, resp, nil)
+}
+
+func (server *HttpServer) GetTokenRecoverApprovalBatch(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		server.Response(w, InvalidRequest, nil, err)
+		return
+	}
+	defer r.Body.Close()
+	req := &approval.GetTokenRecoverApprovalBatchRequest{}
+	err = json.Unmarshal(body, req)
+	if err != nil {
+		server.Response(w, InvalidRequest, nil, err)
+		return
+	}
+	server.logger.Info().Interface("request", req).Msg("GetTokenRecoverApprovalBatch")
+
+	err = req.Validate()
+	if err != nil {
+		server.Response(w, InvalidRequest, nil, err)
+		return
+	}
+
+	resp, err := server.approvalService.GetTokenRecoverApprovalBatch(req)
+	if err != nil {
+		server.Response(w, ErrorCode(err), nil, err)
+		return
+	}
+
+	server.Response(w, Success, newBatchResponse(resp), nil)
+}
+
+func (server *HttpServer) GetAccountAssets(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	address, err := types.AccAddressFromBech32(ps.ByName("address"))
 	if err != nil {
 		server.Response(w, InvalidRequest, nil, err)
 		return
 	}
+	server.logger.Info().Str("address", address.String()).Msg("GetAccountAssets")
+
+	resp, err := server.approvalService.GetAccountAssets(address)
+	if err != nil {
+		server.Response(w, ErrorCode(err), nil, err)
+		return
+	}
+
+	server.Response(w, Success, resp, nil)
+}
+
+func (server *HttpServer) GetAccountAssetProof(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	address, err := types.AccAddressFromBech32(ps.ByName("address"))
+	if err != nil {
+		server.Response(w, InvalidRequest, nil, err)
+		return
+	}
+	symbol := ps.ByName("symbol")
+	server.logger.Info().Str("address", address.String()).Str("symbol", symbol).Msg("GetAccountAssetProof")
+
+	resp, err := server.approvalService.GetAccountAssetProof(address, symbol)
+	if err != nil {
+		server.Response(w, ErrorCode(err), nil, err)
+		return
+	}
+
+	server.Response(w, Success, resp, nil)
+}
+
+func (server *HttpServer) RotateSigners(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	server.logger.Info().Str("remote_addr", r.RemoteAddr).Msg("RotateSigners")
+
+	resp, err := server.approvalService.RotateSigners()
+	if err != nil {
+		server.Response(w, ErrorCode(err), nil, err)
+		return
+	}
 
 	server.Response(w, Success, resp, nil)
 }
@@ -52,6 +140,6 @@ func (server *HttpServer) Response(w http.ResponseWriter, code ResponseCode, dat
 	if err != nil {
 		resp.Error = err.Error()
 	}
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(code.HTTPStatus())
 	fmt.Fprint(w, resp.Marshal())
 }
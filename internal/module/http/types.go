@@ -1,14 +1,79 @@
 package http
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bnb-chain/token-recover-approver/internal/module/approval"
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
+)
 
 type ResponseCode int
 
+// note: append new codes to the end, the values are part of the API
 const (
 	Success ResponseCode = iota
 	InvalidRequest
+	InvalidOwnerSignature
+	NotWhitelisted
+	ProofNotFound
+	InvalidMerkleProof
+	ZeroAmount
+	DoubleClaim
+	InternalError
+	StoreUnavailable
+	Unauthorized
 )
 
+var httpStatus = map[ResponseCode]int{
+	Success:               http.StatusOK,
+	InvalidRequest:        http.StatusBadRequest,
+	InvalidOwnerSignature: http.StatusUnprocessableEntity,
+	NotWhitelisted:        http.StatusForbidden,
+	ProofNotFound:         http.StatusNotFound,
+	InvalidMerkleProof:    http.StatusUnprocessableEntity,
+	ZeroAmount:            http.StatusUnprocessableEntity,
+	DoubleClaim:           http.StatusForbidden,
+	InternalError:         http.StatusInternalServerError,
+	StoreUnavailable:      http.StatusServiceUnavailable,
+	Unauthorized:          http.StatusUnauthorized,
+}
+
+// HTTPStatus returns the http status code of the response code.
+func (code ResponseCode) HTTPStatus() int {
+	status, ok := httpStatus[code]
+	if !ok {
+		return http.StatusInternalServerError
+	}
+	return status
+}
+
+// ErrorCode maps an error returned by the services to a response code.
+func ErrorCode(err error) ResponseCode {
+	switch {
+	case errors.Is(err, approval.ErrInvalidRequest), errors.Is(err, keymanager.ErrNoNextKey):
+		return InvalidRequest
+	case errors.Is(err, approval.ErrInvalidOwnerSignature):
+		return InvalidOwnerSignature
+	case errors.Is(err, approval.ErrNotWhitelisted):
+		return NotWhitelisted
+	case errors.Is(err, store.ErrProofNotFound):
+		return ProofNotFound
+	case errors.Is(err, approval.ErrInvalidMerkleProof):
+		return InvalidMerkleProof
+	case errors.Is(err, approval.ErrZeroAmount), errors.Is(err, store.ErrInvalidToken):
+		return ZeroAmount
+	case errors.Is(err, approval.ErrDoubleClaim):
+		return DoubleClaim
+	case errors.Is(err, store.ErrStoreUnavailable):
+		return StoreUnavailable
+	default:
+		return InternalError
+	}
+}
+
 type Response struct {
 	Code  ResponseCode `json:"code"`
 	Data  interface{}  `json:"data,omitempty"`
@@ -19,3 +84,31 @@ func (r *Response) Marshal() string {
 	b, _ := json.Marshal(r)
 	return string(b)
 }
+
+type BatchResponse struct {
+	Items []*BatchItemResponse `json:"items"`
+}
+
+type BatchItemResponse struct {
+	TokenSymbol string                                    `json:"token_symbol"`
+	Code        ResponseCode                              `json:"code"`
+	Approval    *approval.GetTokenRecoverApprovalResponse `json:"approval,omitempty"`
+	Error       string                                    `json:"error,omitempty"`
+}
+
+func newBatchResponse(resp *approval.GetTokenRecoverApprovalBatchResponse) *BatchResponse {
+	items := make([]*BatchItemResponse, 0, len(resp.Items))
+	for _, result := range resp.Items {
+		item := &BatchItemResponse{
+			TokenSymbol: result.TokenSymbol,
+			Code:        Success,
+			Approval:    result.Approval,
+		}
+		if result.Err != nil {
+			item.Code = ErrorCode(result.Err)
+			item.Error = result.Err.Error()
+		}
+		items = append(items, item)
+	}
+	return &BatchResponse{Items: items}
+}
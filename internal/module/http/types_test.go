@@ -0,0 +1,73 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bnb-chain/token-recover-approver/internal/module/approval"
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   ResponseCode
+		status int
+	}{
+		{approval.ErrInvalidRequest, InvalidRequest, http.StatusBadRequest},
+		{keymanager.ErrNoNextKey, InvalidRequest, http.StatusBadRequest},
+		{approval.ErrInvalidOwnerSignature, InvalidOwnerSignature, http.StatusUnprocessableEntity},
+		{approval.ErrNotWhitelisted, NotWhitelisted, http.StatusForbidden},
+		{store.ErrProofNotFound, ProofNotFound, http.StatusNotFound},
+		{approval.ErrInvalidMerkleProof, InvalidMerkleProof, http.StatusUnprocessableEntity},
+		{approval.ErrZeroAmount, ZeroAmount, http.StatusUnprocessableEntity},
+		{store.ErrInvalidToken, ZeroAmount, http.StatusUnprocessableEntity},
+		{approval.ErrDoubleClaim, DoubleClaim, http.StatusForbidden},
+		{store.ErrStoreUnavailable, StoreUnavailable, http.StatusServiceUnavailable},
+		{errors.New("sign failed"), InternalError, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		for _, err := range []error{tt.err, fmt.Errorf("get approval: %w", tt.err)} {
+			code := ErrorCode(err)
+			if code != tt.code {
+				t.Errorf("ErrorCode(%q) = %d, want %d", err, code, tt.code)
+			}
+			if status := code.HTTPStatus(); status != tt.status {
+				t.Errorf("ErrorCode(%q).HTTPStatus() = %d, want %d", err, status, tt.status)
+			}
+		}
+	}
+}
+
+func TestResponseCode_HTTPStatus(t *testing.T) {
+	// the codes are part of the API, see the response codes table of the Readme
+	tests := []struct {
+		code   ResponseCode
+		value  int
+		status int
+	}{
+		{Success, 0, http.StatusOK},
+		{InvalidRequest, 1, http.StatusBadRequest},
+		{InvalidOwnerSignature, 2, http.StatusUnprocessableEntity},
+		{NotWhitelisted, 3, http.StatusForbidden},
+		{ProofNotFound, 4, http.StatusNotFound},
+		{InvalidMerkleProof, 5, http.StatusUnprocessableEntity},
+		{ZeroAmount, 6, http.StatusUnprocessableEntity},
+		{DoubleClaim, 7, http.StatusForbidden},
+		{InternalError, 8, http.StatusInternalServerError},
+		{StoreUnavailable, 9, http.StatusServiceUnavailable},
+		{Unauthorized, 10, http.StatusUnauthorized},
+		{ResponseCode(99), 99, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if int(tt.code) != tt.value {
+			t.Errorf("code %d, want %d", tt.code, tt.value)
+		}
+		if status := tt.code.HTTPStatus(); status != tt.status {
+			t.Errorf("ResponseCode(%d).HTTPStatus() = %d, want %d", tt.code, status, tt.status)
+		}
+	}
+}
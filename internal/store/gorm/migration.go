@@ -21,6 +21,98 @@ var v202311021600 = &gormigrate.Migration{
 	},
 }
 
+// approvalV202403011200 is the approvals table created by v202403011200, the migrations of the approvals
+// table use the schema of their version so that an older database is upgraded one version at a time.
+type approvalV202403011200 struct {
+	gorm.Model
+	Address            string `gorm:"index:idx_approval_address_denom;type:varchar(42)"`
+	Denom              string `gorm:"index:idx_approval_address_denom;type:varchar(32)"`
+	Amount             int64
+	ClaimAddress       string `gorm:"type:varchar(42)"`
+	OwnerSignatureHash string `gorm:"type:varchar(66)"`
+	ApprovalSignature  string `gorm:"type:text"`
+}
+
+func (approvalV202403011200) TableName() string {
+	return "approvals"
+}
+
+var v202403011200 = &gormigrate.Migration{
+	ID: "202403011200",
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&approvalV202403011200{}); err != nil {
+			return err
+		}
+		return nil
+	},
+	Rollback: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropTable(&approvalV202403011200{}); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+// approvalV202403151200 adds the signed deadline of the approval.
+type approvalV202403151200 struct {
+	approvalV202403011200
+	Deadline uint64
+}
+
+var v202403151200 = &gormigrate.Migration{
+	ID: "202403151200",
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&approvalV202403151200{}); err != nil {
+			return err
+		}
+		return nil
+	},
+	Rollback: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropColumn(&approvalV202403151200{}, "Deadline"); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+// approvalV202403201200 adds the signers of the approval signatures.
+type approvalV202403201200 struct {
+	approvalV202403151200
+	Signers string `gorm:"type:text"`
+}
+
+var v202403201200 = &gormigrate.Migration{
+	ID: "202403201200",
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&approvalV202403201200{}); err != nil {
+			return err
+		}
+		return nil
+	},
+	Rollback: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropColumn(&approvalV202403201200{}, "Signers"); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+var v202404011200 = &gormigrate.Migration{
+	ID: "202404011200",
+	Migrate: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&Claim{}); err != nil {
+			return err
+		}
+		return nil
+	},
+	Rollback: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropTable(&Claim{}); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
 // Version is a migrate version of database
 type Version struct {
 	ID   int64
@@ -30,4 +122,8 @@ type Version struct {
 // Migrations is a collection of storage migration patterns
 var Migrations = []*gormigrate.Migration{
 	v202311021600,
+	v202403011200,
+	v202403151200,
+	v202403201200,
+	v202404011200,
 }
@@ -0,0 +1,62 @@
+package gorm
+
+import (
+	"testing"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm/logger"
+)
+
+func TestMigrations_Approvals(t *testing.T) {
+	s, err := OpenSQLStore(makeSQLiteConfig(t), SetLogLevel(logger.Silent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	migrator := s.db.Migrator()
+
+	// every version adds its own columns to the table of the version before
+	steps := []struct {
+		migration *gormigrate.Migration
+		columns   map[string]bool
+	}{
+		{v202403011200, map[string]bool{"address": true, "approval_signature": true, "deadline": false, "signers": false}},
+		{v202403151200, map[string]bool{"deadline": true, "signers": false}},
+		{v202403201200, map[string]bool{"signers": true}},
+	}
+	for _, step := range steps {
+		if err := step.migration.Migrate(s.db); err != nil {
+			t.Fatalf("migrate %s: %v", step.migration.ID, err)
+		}
+		for column, want := range step.columns {
+			if got := migrator.HasColumn("approvals", column); got != want {
+				t.Errorf("after %s column %s exists = %v, want %v", step.migration.ID, column, got, want)
+			}
+		}
+	}
+	if !migrator.HasIndex("approvals", "idx_approval_address_denom") {
+		t.Error("index idx_approval_address_denom is not created")
+	}
+
+	// the last version is the schema of the model
+	for _, field := range []string{"Address", "Denom", "Amount", "ClaimAddress", "OwnerSignatureHash", "ApprovalSignature", "Signers", "Deadline"} {
+		if !migrator.HasColumn(&Approval{}, field) {
+			t.Errorf("column of Approval.%s is not migrated", field)
+		}
+	}
+
+	for _, rollback := range []struct {
+		migration *gormigrate.Migration
+		column    string
+	}{
+		{v202403201200, "signers"},
+		{v202403151200, "deadline"},
+	} {
+		if err := rollback.migration.Rollback(s.db); err != nil {
+			t.Fatal(err)
+		}
+		if migrator.HasColumn("approvals", rollback.column) {
+			t.Errorf("column %s is not dropped by the rollback of %s", rollback.column, rollback.migration.ID)
+		}
+	}
+}
@@ -11,3 +11,23 @@ type Proof struct {
 	Proof   string `json:"proof" gorm:"type:text"` // hex encoded
 	Amount  int64  `json:"amount"`
 }
+
+type Approval struct {
+	gorm.Model
+	Address            string `json:"address" gorm:"index:idx_approval_address_denom;type:varchar(42)"` // hex encoded
+	Denom              string `json:"denom" gorm:"index:idx_approval_address_denom;type:varchar(32)"`
+	Amount             int64  `json:"amount"`
+	ClaimAddress       string `json:"claim_address" gorm:"type:varchar(42)"`        // hex encoded
+	OwnerSignatureHash string `json:"owner_signature_hash" gorm:"type:varchar(66)"` // hex encoded
+	ApprovalSignature  string `json:"approval_signature" gorm:"type:text"`          // hex encoded, comma separated
+	Signers            string `json:"signers" gorm:"type:text"`                     // hex encoded, comma separated
+	Deadline           uint64 `json:"deadline"`
+}
+
+// Claim binds an account asset to the claim address of its first approval under the reject policy.
+type Claim struct {
+	gorm.Model
+	Address      string `json:"address" gorm:"uniqueIndex:idx_claim_address_denom;type:varchar(42)"` // hex encoded
+	Denom        string `json:"denom" gorm:"uniqueIndex:idx_claim_address_denom;type:varchar(32)"`
+	ClaimAddress string `json:"claim_address" gorm:"type:varchar(42)"` // hex encoded
+}
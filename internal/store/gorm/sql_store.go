@@ -2,17 +2,20 @@ package gorm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	// database driver for gorm
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 
 	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/bnb-chain/token-recover-approver/internal/config"
 	"github.com/bnb-chain/token-recover-approver/internal/store"
@@ -43,9 +46,31 @@ var _supportedDataSource = map[DataSourceTypeName]func(port uint, host, dbname,
 	},
 }
 
-var _ store.Store = (*SQLStore)(nil)
+// iterateBatchSize is the number of proofs read by a query of Iterate
+const iterateBatchSize = 1000
 
+var (
+	_ store.Store         = (*SQLStore)(nil)
+	_ store.ApprovalStore = (*SQLStore)(nil)
+)
+
+// NewSQLStore connects to the database and migrates it to the latest schema.
 func NewSQLStore(config *config.Config, options ...Option) (*SQLStore, error) {
+	s, err := OpenSQLStore(config, options...)
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range Migrations {
+		err := migration.Migrate(s.db)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// OpenSQLStore connects to the database without migrating it, for reading a store another approver owns.
+func OpenSQLStore(config *config.Config, options ...Option) (*SQLStore, error) {
 	supported, ok := _supportedDataSource[DataSourceTypeName(config.Store.SqlStore.SQLDriver)]
 	if !ok {
 		return nil, fmt.Errorf("unsupported database driver: %s", config.Store.SqlStore.SQLDriver)
@@ -85,19 +110,12 @@ func NewSQLStore(config *config.Config, options ...Option) (*SQLStore, error) {
 		}
 	}
 
-	for _, migration := range Migrations {
-		err := migration.Migrate(engine)
-		if err != nil {
-			return nil, err
-		}
-	}
-
 	return &SQLStore{
 		db: engine,
 	}, nil
 }
 
-// SQLStore implements store.Store.
+// SQLStore implements store.Store and store.ApprovalStore.
 type SQLStore struct {
 	db *gorm.DB
 }
@@ -106,8 +124,11 @@ type SQLStore struct {
 func (s *SQLStore) GetAccountAssetProof(address types.AccAddress, symbol string) (*store.Proof, error) {
 	var proof Proof
 	result := s.db.Where("address = ? AND denom = ?", util.EncodeBytesToHex(address), symbol).First(&proof)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, store.ErrProofNotFound
+	}
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, storeError(result.Error)
 	}
 
 	return &store.Proof{
@@ -118,6 +139,26 @@ func (s *SQLStore) GetAccountAssetProof(address types.AccAddress, symbol string)
 	}, nil
 }
 
+// ListAccountAssetProofs implements store.Store.
+func (s *SQLStore) ListAccountAssetProofs(address types.AccAddress) ([]*store.Proof, error) {
+	var dbProofs []Proof
+	result := s.db.Where("address = ?", util.EncodeBytesToHex(address)).Order("denom").Find(&dbProofs)
+	if result.Error != nil {
+		return nil, storeError(result.Error)
+	}
+
+	proofs := make([]*store.Proof, 0, len(dbProofs))
+	for _, proof := range dbProofs {
+		proofs = append(proofs, &store.Proof{
+			Address: types.AccAddress(util.MustDecodeHexToBytes(proof.Address)),
+			Denom:   proof.Denom,
+			Amount:  proof.Amount,
+			Proof:   util.MustDecodeHexArrayToBytes(strings.Split(proof.Proof, ",")),
+		})
+	}
+	return proofs, nil
+}
+
 // InsertAccountProof implements a function to insert account proof.
 func (s *SQLStore) InsertAccountAssetProof(proof *store.Proof) error {
 	dbProof := &Proof{
@@ -129,7 +170,7 @@ func (s *SQLStore) InsertAccountAssetProof(proof *store.Proof) error {
 
 	result := s.db.Create(dbProof)
 	if result.Error != nil {
-		return result.Error
+		return storeError(result.Error)
 	}
 
 	return nil
@@ -140,11 +181,111 @@ func (s *SQLStore) CountAccountAssetProofs() (int64, error) {
 	var count int64
 	result := s.db.Model(&Proof{}).Count(&count)
 	if result.Error != nil {
-		return 0, result.Error
+		return 0, storeError(result.Error)
 	}
 	return count, nil
 }
 
+// Iterate implements store.Store, the proofs are read in batches of iterateBatchSize in the order of id.
+func (s *SQLStore) Iterate(fn func(proof *store.Proof) error) error {
+	var (
+		dbProofs []Proof
+		fnErr    error
+	)
+	result := s.db.FindInBatches(&dbProofs, iterateBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, proof := range dbProofs {
+			fnErr = fn(&store.Proof{
+				Address: types.AccAddress(util.MustDecodeHexToBytes(proof.Address)),
+				Denom:   proof.Denom,
+				Amount:  proof.Amount,
+				Proof:   util.MustDecodeHexArrayToBytes(strings.Split(proof.Proof, ",")),
+			})
+			if fnErr != nil {
+				return fnErr
+			}
+		}
+		return nil
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if result.Error != nil {
+		return storeError(result.Error)
+	}
+	return nil
+}
+
+// GetApprovals implements store.ApprovalStore.
+func (s *SQLStore) GetApprovals(address types.AccAddress, symbol string) ([]*store.Approval, error) {
+	var dbApprovals []Approval
+	result := s.db.Where("address = ? AND denom = ?", util.EncodeBytesToHex(address), symbol).Order("id").Find(&dbApprovals)
+	if result.Error != nil {
+		return nil, storeError(result.Error)
+	}
+
+	approvals := make([]*store.Approval, 0, len(dbApprovals))
+	for _, approval := range dbApprovals {
+		approvals = append(approvals, &store.Approval{
+			Address:            types.AccAddress(util.MustDecodeHexToBytes(approval.Address)),
+			Denom:              approval.Denom,
+			Amount:             approval.Amount,
+			ClaimAddress:       common.HexToAddress(approval.ClaimAddress),
+			OwnerSignatureHash: util.MustDecodeHexToBytes(approval.OwnerSignatureHash),
+			ApprovalSignatures: util.MustDecodeHexArrayToBytes(splitList(approval.ApprovalSignature)),
+			Signers:            decodeAddresses(splitList(approval.Signers)),
+			Deadline:           approval.Deadline,
+			CreatedAt:          approval.CreatedAt,
+		})
+	}
+	return approvals, nil
+}
+
+// InsertApproval implements store.ApprovalStore.
+func (s *SQLStore) InsertApproval(approval *store.Approval) error {
+	dbApproval := &Approval{
+		Address:            util.EncodeBytesToHex(approval.Address),
+		Denom:              approval.Denom,
+		Amount:             approval.Amount,
+		ClaimAddress:       approval.ClaimAddress.Hex(),
+		OwnerSignatureHash: util.EncodeBytesToHex(approval.OwnerSignatureHash),
+		ApprovalSignature:  strings.Join(util.EncodeBytesArrayToHex(approval.ApprovalSignatures), ","),
+		Signers:            strings.Join(encodeAddresses(approval.Signers), ","),
+		Deadline:           approval.Deadline,
+	}
+
+	result := s.db.Create(dbApproval)
+	if result.Error != nil {
+		return storeError(result.Error)
+	}
+	approval.CreatedAt = dbApproval.CreatedAt
+
+	return nil
+}
+
+// ReserveClaim implements store.ApprovalStore.
+// The unique index on the account asset makes the insert of a concurrent reservation a no-op.
+func (s *SQLStore) ReserveClaim(address types.AccAddress, symbol string, claimAddress common.Address) (common.Address, error) {
+	claim := &Claim{
+		Address:      util.EncodeBytesToHex(address),
+		Denom:        symbol,
+		ClaimAddress: claimAddress.Hex(),
+	}
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(claim)
+	if result.Error != nil {
+		return common.Address{}, storeError(result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return claimAddress, nil
+	}
+
+	var reserved Claim
+	result = s.db.Where("address = ? AND denom = ?", claim.Address, symbol).First(&reserved)
+	if result.Error != nil {
+		return common.Address{}, storeError(result.Error)
+	}
+	return common.HexToAddress(reserved.ClaimAddress), nil
+}
+
 // Close implements store.Store.
 func (s *SQLStore) Close() error {
 	db, err := s.db.DB()
@@ -153,3 +294,31 @@ func (s *SQLStore) Close() error {
 	}
 	return db.Close()
 }
+
+func splitList(list string) []string {
+	if len(list) == 0 {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+func encodeAddresses(addresses []common.Address) []string {
+	hex := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		hex = append(hex, address.Hex())
+	}
+	return hex
+}
+
+func decodeAddresses(hex []string) []common.Address {
+	addresses := make([]common.Address, 0, len(hex))
+	for _, v := range hex {
+		addresses = append(addresses, common.HexToAddress(v))
+	}
+	return addresses
+}
+
+// storeError marks a database error as a store outage.
+func storeError(err error) error {
+	return fmt.Errorf("%w: %v", store.ErrStoreUnavailable, err)
+}
@@ -0,0 +1,83 @@
+package gorm
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"gorm.io/gorm/logger"
+
+	"github.com/bnb-chain/token-recover-approver/internal/config"
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+)
+
+// makeSQLiteConfig returns the config of a sqlite database in a temporary directory.
+func makeSQLiteConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Store.SqlStore.SQLDriver = string(Sqlite)
+	cfg.Store.SqlStore.DBName = filepath.Join(t.TempDir(), "approver")
+	cfg.Store.SqlStore.DialTimeout = 10 * time.Second
+	return cfg
+}
+
+func makeSQLStore(t *testing.T) *SQLStore {
+	t.Helper()
+	s, err := NewSQLStore(makeSQLiteConfig(t), SetLogLevel(logger.Silent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestSQLStore_AccountAssetProofs(t *testing.T) {
+	alice := sdk.AccAddress(bytes.Repeat([]byte{1}, sdk.AddrLen))
+	bob := sdk.AccAddress(bytes.Repeat([]byte{2}, sdk.AddrLen))
+	node := bytes.Repeat([]byte{3}, 32)
+	s := makeSQLStore(t)
+	for _, proof := range []*store.Proof{
+		{Address: alice, Denom: "XYZ-456", Amount: 3, Proof: [][]byte{node}},
+		{Address: alice, Denom: "BNB", Amount: 50, Proof: [][]byte{node, node}},
+		{Address: bob, Denom: "BNB", Amount: 100, Proof: [][]byte{node}},
+	} {
+		if err := s.InsertAccountAssetProof(proof); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []*store.Proof{
+		{Address: alice, Denom: "BNB", Amount: 50, Proof: [][]byte{node, node}},
+		{Address: alice, Denom: "XYZ-456", Amount: 3, Proof: [][]byte{node}},
+	}
+	proofs, err := s.ListAccountAssetProofs(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(proofs, want) {
+		t.Fatalf("ListAccountAssetProofs() = %+v, want %+v", proofs, want)
+	}
+	for _, proof := range want {
+		got, err := s.GetAccountAssetProof(alice, proof.Denom)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, proof) {
+			t.Errorf("GetAccountAssetProof(%s) = %+v, want %+v", proof.Denom, got, proof)
+		}
+	}
+
+	if proofs, err := s.ListAccountAssetProofs(sdk.AccAddress(bytes.Repeat([]byte{4}, sdk.AddrLen))); err != nil || len(proofs) != 0 {
+		t.Errorf("ListAccountAssetProofs() of an unknown account = %+v, %v", proofs, err)
+	}
+	if _, err := s.GetAccountAssetProof(bob, "XYZ-456"); !errors.Is(err, store.ErrProofNotFound) {
+		t.Errorf("GetAccountAssetProof() of an unknown asset error = %v", err)
+	}
+	if count, err := s.CountAccountAssetProofs(); err != nil || count != 3 {
+		t.Errorf("CountAccountAssetProofs() = %d, %v, want 3", count, err)
+	}
+}
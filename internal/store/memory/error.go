@@ -1,9 +1,13 @@
 package memory
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+)
 
 var (
 	ErrAccountNotFound = errors.New("account not found")
 	ErrAssetNotFound   = errors.New("asset not found")
-	ErrProofNotFound   = errors.New("proof not found")
+	ErrProofNotFound   = store.ErrProofNotFound
 )
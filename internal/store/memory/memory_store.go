@@ -1,13 +1,21 @@
 package memory
 
 import (
+	"sort"
+	"sync"
+	"time"
+
 	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/bnb-chain/token-recover-approver/internal/store"
 	"github.com/bnb-chain/token-recover-approver/pkg/util"
 )
 
-var _ store.Store = (*MemoryStore)(nil)
+var (
+	_ store.Store         = (*MemoryStore)(nil)
+	_ store.ApprovalStore = (*MemoryStore)(nil)
+)
 
 func NewMemoryStore(proofsPath string) (*MemoryStore, error) {
 	errChan := make(chan error, 1)
@@ -18,6 +26,8 @@ func NewMemoryStore(proofsPath string) (*MemoryStore, error) {
 	})
 
 	proofs := make(map[string]*Proof)
+	indexes := make([]string, 0)
+	accounts := make(map[string][]*Proof)
 	go func() {
 		for data := range stream.Watch() {
 			if data.Error != nil {
@@ -26,7 +36,20 @@ func NewMemoryStore(proofsPath string) (*MemoryStore, error) {
 			}
 			proof := data.Data.(*Proof)
 			index := proof.Address.String() + ":" + proof.Coin.Denom
+			// a repeated asset replaces the proof loaded before, in the index and in the account
+			old, exist := proofs[index]
 			proofs[index] = proof
+			if !exist {
+				indexes = append(indexes, index)
+				accounts[proof.Address.String()] = append(accounts[proof.Address.String()], proof)
+				continue
+			}
+			accountProofs := accounts[proof.Address.String()]
+			for i := range accountProofs {
+				if accountProofs[i] == old {
+					accountProofs[i] = proof
+				}
+			}
 		}
 		errChan <- nil
 	}()
@@ -36,14 +59,30 @@ func NewMemoryStore(proofsPath string) (*MemoryStore, error) {
 		return nil, err
 	}
 
+	for _, accountProofs := range accounts {
+		sort.Slice(accountProofs, func(i, j int) bool {
+			return accountProofs[i].Coin.Denom < accountProofs[j].Coin.Denom
+		})
+	}
+
 	return &MemoryStore{
-		proofs: proofs,
+		proofs:    proofs,
+		indexes:   indexes,
+		accounts:  accounts,
+		approvals: make(map[string][]*store.Approval),
+		claims:    make(map[string]common.Address),
 	}, nil
 }
 
-// MemoryStore implements store.Store.
+// MemoryStore implements store.Store and store.ApprovalStore.
 type MemoryStore struct {
-	proofs map[string]*Proof // address:index:symbol -> proofs
+	proofs   map[string]*Proof   // address:index:symbol -> proofs
+	indexes  []string            // address:symbol in the order of the proofs file
+	accounts map[string][]*Proof // address -> proofs sorted by denom
+
+	mu        sync.RWMutex
+	approvals map[string][]*store.Approval // address:symbol -> approvals
+	claims    map[string]common.Address    // address:symbol -> reserved claim address
 }
 
 // GetAccountProofs implements store.Store.
@@ -62,11 +101,80 @@ func (ss *MemoryStore) GetAccountAssetProof(address types.AccAddress, symbol str
 	}, nil
 }
 
+// ListAccountAssetProofs implements store.Store.
+func (ss *MemoryStore) ListAccountAssetProofs(address types.AccAddress) ([]*store.Proof, error) {
+	accountProofs := ss.accounts[address.String()]
+	proofs := make([]*store.Proof, 0, len(accountProofs))
+	for _, proof := range accountProofs {
+		proofs = append(proofs, &store.Proof{
+			Address: proof.Address,
+			Denom:   proof.Coin.Denom,
+			Amount:  proof.Coin.Amount,
+			Proof:   proof.Proof,
+		})
+	}
+	return proofs, nil
+}
+
 // CountAccountAssetProofs implements store.Store.
 func (ss *MemoryStore) CountAccountAssetProofs() (count int64, err error) {
 	return int64(len(ss.proofs)), nil
 }
 
+// Iterate implements store.Store, the proofs are iterated in the order of the proofs file.
+func (ss *MemoryStore) Iterate(fn func(proof *store.Proof) error) error {
+	for _, index := range ss.indexes {
+		proof := ss.proofs[index]
+		err := fn(&store.Proof{
+			Address: proof.Address,
+			Denom:   proof.Coin.Denom,
+			Amount:  proof.Coin.Amount,
+			Proof:   proof.Proof,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetApprovals implements store.ApprovalStore.
+func (ss *MemoryStore) GetApprovals(address types.AccAddress, symbol string) ([]*store.Approval, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	index := address.String() + ":" + symbol
+	approvals := make([]*store.Approval, len(ss.approvals[index]))
+	copy(approvals, ss.approvals[index])
+	return approvals, nil
+}
+
+// InsertApproval implements store.ApprovalStore.
+func (ss *MemoryStore) InsertApproval(approval *store.Approval) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if approval.CreatedAt.IsZero() {
+		approval.CreatedAt = time.Now().UTC()
+	}
+	index := approval.Address.String() + ":" + approval.Denom
+	ss.approvals[index] = append(ss.approvals[index], approval)
+	return nil
+}
+
+// ReserveClaim implements store.ApprovalStore.
+func (ss *MemoryStore) ReserveClaim(address types.AccAddress, symbol string, claimAddress common.Address) (common.Address, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	index := address.String() + ":" + symbol
+	if reserved, ok := ss.claims[index]; ok {
+		return reserved, nil
+	}
+	ss.claims[index] = claimAddress
+	return claimAddress, nil
+}
+
 // Close implements store.Store.
 func (ss *MemoryStore) Close() error {
 	return nil
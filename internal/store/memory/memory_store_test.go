@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/bnb-chain/token-recover-approver/internal/store"
+)
+
+func writeProofs(t *testing.T, proofs Proofs) string {
+	t.Helper()
+	data, err := json.Marshal(proofs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "merkle_proofs.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMemoryStore_AccountAssetProofs(t *testing.T) {
+	alice := sdk.AccAddress(bytes.Repeat([]byte{1}, sdk.AddrLen))
+	bob := sdk.AccAddress(bytes.Repeat([]byte{2}, sdk.AddrLen))
+	node := bytes.Repeat([]byte{3}, 32)
+	path := writeProofs(t, Proofs{
+		{Address: alice, Coin: sdk.Coin{Denom: "XYZ-456", Amount: 3}, Proof: [][]byte{node}},
+		{Address: alice, Coin: sdk.Coin{Denom: "BNB", Amount: 50}, Proof: [][]byte{node}},
+		{Address: bob, Coin: sdk.Coin{Denom: "BNB", Amount: 100}, Proof: [][]byte{node}},
+		// the repeated asset replaces the first one
+		{Address: alice, Coin: sdk.Coin{Denom: "BNB", Amount: 80}, Proof: [][]byte{node, node}},
+	})
+	s, err := NewMemoryStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*store.Proof{
+		{Address: alice, Denom: "BNB", Amount: 80, Proof: [][]byte{node, node}},
+		{Address: alice, Denom: "XYZ-456", Amount: 3, Proof: [][]byte{node}},
+	}
+	proofs, err := s.ListAccountAssetProofs(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(proofs, want) {
+		t.Fatalf("ListAccountAssetProofs() = %+v, want %+v", proofs, want)
+	}
+	for _, proof := range want {
+		got, err := s.GetAccountAssetProof(alice, proof.Denom)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, proof) {
+			t.Errorf("GetAccountAssetProof(%s) = %+v, want %+v", proof.Denom, got, proof)
+		}
+	}
+
+	if proofs, err := s.ListAccountAssetProofs(sdk.AccAddress(bytes.Repeat([]byte{4}, sdk.AddrLen))); err != nil || len(proofs) != 0 {
+		t.Errorf("ListAccountAssetProofs() of an unknown account = %+v, %v", proofs, err)
+	}
+	if _, err := s.GetAccountAssetProof(bob, "XYZ-456"); !errors.Is(err, ErrProofNotFound) {
+		t.Errorf("GetAccountAssetProof() of an unknown asset error = %v", err)
+	}
+
+	if count, err := s.CountAccountAssetProofs(); err != nil || count != 3 {
+		t.Errorf("CountAccountAssetProofs() = %d, %v, want 3", count, err)
+	}
+	var iterated []string
+	err = s.Iterate(func(proof *store.Proof) error {
+		iterated = append(iterated, proof.Denom)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(iterated, []string{"XYZ-456", "BNB", "BNB"}) {
+		t.Errorf("Iterate() denoms = %v", iterated)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/bnb-chain/token-recover-approver/pkg/util"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 type Account struct {
@@ -23,6 +24,22 @@ type Proof struct {
 	Proof   [][]byte       `json:"proof"`
 }
 
+func (p *Proof) MarshalJSON() ([]byte, error) {
+	proof := make([]string, 0, len(p.Proof))
+	for _, node := range p.Proof {
+		proof = append(proof, hexutil.Encode(node))
+	}
+	return json.Marshal(struct {
+		Address sdk.AccAddress `json:"address"`
+		Coin    sdk.Coin       `json:"coin"`
+		Proof   []string       `json:"proof"`
+	}{
+		Address: p.Address,
+		Coin:    p.Coin,
+		Proof:   proof,
+	})
+}
+
 func (p *Proof) UnmarshalJSON(data []byte) error {
 	var source = struct {
 		Address sdk.AccAddress `json:"address"`
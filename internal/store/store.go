@@ -2,10 +2,25 @@ package store
 
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type Store interface {
 	GetAccountAssetProof(address sdk.AccAddress, symbol string) (proofs *Proof, err error)
+	// ListAccountAssetProofs returns the proofs of every asset held by the account, sorted by denom.
+	ListAccountAssetProofs(address sdk.AccAddress) (proofs []*Proof, err error)
 	CountAccountAssetProofs() (count int64, err error)
+	// Iterate streams every proof in the store to fn, it stops at the first error of fn and returns it.
+	Iterate(fn func(proof *Proof) error) error
 	Close() error
 }
+
+// ApprovalStore is a ledger of every approval issued by the approver.
+type ApprovalStore interface {
+	// GetApprovals returns the approvals issued for the account asset, oldest first.
+	GetApprovals(address sdk.AccAddress, symbol string) (approvals []*Approval, err error)
+	InsertApproval(approval *Approval) error
+	// ReserveClaim binds the account asset to the claim address if it is not bound yet,
+	// and returns the claim address it is bound to. Concurrent reservations bind only one claim address.
+	ReserveClaim(address sdk.AccAddress, symbol string, claimAddress common.Address) (reserved common.Address, err error)
+}
@@ -3,14 +3,20 @@ package store
 import (
 	"errors"
 	"math/big"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
 var (
 	// ErrInvalidToken is returned when the token is not found in the merkle tree
 	ErrInvalidToken = errors.New("invalid token")
+	// ErrProofNotFound is returned when the account asset has no proof in the store
+	ErrProofNotFound = errors.New("proof not found")
+	// ErrStoreUnavailable is returned when the store backend fails
+	ErrStoreUnavailable = errors.New("store unavailable")
 )
 
 // Proof is a merkle proof of an account
@@ -35,3 +41,16 @@ func (p *Proof) Serialize() ([]byte, error) {
 		big.NewInt(p.Amount).FillBytes(make([]byte, 32)),
 	).Bytes(), nil
 }
+
+// Approval is an issued approval of a token recover request
+type Approval struct {
+	Address            sdk.AccAddress   `json:"address"`
+	Denom              string           `json:"denom"`
+	Amount             int64            `json:"amount"`
+	ClaimAddress       common.Address   `json:"claim_address"`
+	OwnerSignatureHash []byte           `json:"owner_signature_hash"`
+	ApprovalSignatures [][]byte         `json:"approval_signatures"`
+	Signers            []common.Address `json:"signers"`
+	Deadline           uint64           `json:"deadline"` // unix seconds, zero means no deadline
+	CreatedAt          time.Time        `json:"created_at"`
+}
@@ -0,0 +1,96 @@
+package ethsecp256k1
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidCurveSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+type subjectPublicKeyInfo struct {
+	Algorithm struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.ObjectIdentifier
+	}
+	PublicKey asn1.BitString
+}
+
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// ParsePKIXPublicKey parses a DER encoded SubjectPublicKeyInfo of a secp256k1 key,
+// which crypto/x509 refuses because the curve is not supported by the standard library.
+func ParsePKIXPublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	var spki subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(der, &spki)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data after public key")
+	}
+	if !spki.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) || !spki.Algorithm.Parameters.Equal(oidCurveSecp256k1) {
+		return nil, fmt.Errorf("not a secp256k1 public key: %v %v", spki.Algorithm.Algorithm, spki.Algorithm.Parameters)
+	}
+	return UnmarshalPubKey(spki.PublicKey.RightAlign())
+}
+
+// UnmarshalPubKey parses a compressed or uncompressed secp256k1 public key.
+func UnmarshalPubKey(pub []byte) (*ecdsa.PublicKey, error) {
+	if len(pub) == 33 {
+		return crypto.DecompressPubkey(pub)
+	}
+	return crypto.UnmarshalPubkey(pub)
+}
+
+// SignatureFromDER converts a DER encoded ECDSA signature of the digest into the
+// canonical low-S [R || S || V] format produced by PrivKey.Sign, the recovery id
+// is found by trying both candidates against the signer's public key.
+func SignatureFromDER(digest []byte, der []byte, pubKey *ecdsa.PublicKey) ([]byte, error) {
+	var sig ecdsaSignature
+	rest, err := asn1.Unmarshal(der, &sig)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data after signature")
+	}
+	return SignatureFromRS(digest, sig.R, sig.S, pubKey)
+}
+
+// SignatureFromRS is like SignatureFromDER but takes the raw R and S values.
+func SignatureFromRS(digest []byte, r, s *big.Int, pubKey *ecdsa.PublicKey) ([]byte, error) {
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(secp256k1N) >= 0 || s.Cmp(secp256k1N) >= 0 {
+		return nil, errors.New("invalid signature values")
+	}
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s = new(big.Int).Sub(secp256k1N, s)
+	}
+
+	signature := make([]byte, crypto.SignatureLength)
+	copy(signature[:32], math.PaddedBigBytes(r, 32))
+	copy(signature[32:64], math.PaddedBigBytes(s, 32))
+	want := crypto.FromECDSAPub(pubKey)
+	for v := byte(0); v < 2; v++ {
+		signature[crypto.RecoveryIDOffset] = v
+		recovered, err := crypto.Ecrecover(digest, signature)
+		if err == nil && bytes.Equal(recovered, want) {
+			return signature, nil
+		}
+	}
+	return nil, errors.New("signature does not match public key")
+}
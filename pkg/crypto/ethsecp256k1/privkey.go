@@ -19,6 +19,11 @@ func NewPrivKey(secret string) (*PrivKey, error) {
 	return &PrivKey{ecdsaPrivKey}, nil
 }
 
+// NewPrivKeyFromECDSA creates a new PrivKey from an ecdsa.PrivateKey.
+func NewPrivKeyFromECDSA(privKey *ecdsa.PrivateKey) *PrivKey {
+	return &PrivKey{privKey}
+}
+
 // PrivKey is a wrapper around an ecdsa.PrivateKey
 type PrivKey struct {
 	privKey *ecdsa.PrivateKey
@@ -28,3 +28,15 @@ func (pk *PubKey) Verify(message []byte, signature []byte) (valid bool) {
 	pubKey := crypto.FromECDSAPub(pk.pubKey)
 	return crypto.VerifySignature(pubKey, message, sig)
 }
+
+// VerifyAddress verifies a [R || S || V] signature is signed by the key of the address.
+func VerifyAddress(address common.Address, message []byte, signature []byte) (valid bool) {
+	if len(signature) != crypto.SignatureLength {
+		return false
+	}
+	pubKey, err := crypto.SigToPub(message, signature)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*pubKey) == address && NewPubKey(pubKey).Verify(message, signature)
+}
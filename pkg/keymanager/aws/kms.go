@@ -0,0 +1,81 @@
+package aws
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/crypto/ethsecp256k1"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
+)
+
+var _ keymanager.KeyManager = (*KMSKeyManager)(nil)
+
+// KMSClient is the subset of the KMS API used by KMSKeyManager.
+type KMSClient interface {
+	GetPublicKey(input *kms.GetPublicKeyInput) (*kms.GetPublicKeyOutput, error)
+	Sign(input *kms.SignInput) (*kms.SignOutput, error)
+}
+
+// NewKMSKeyManager creates a key manager signing with an ECC_SECG_P256K1 key in AWS KMS.
+func NewKMSKeyManager(keyID, region string) (*KMSKeyManager, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region: &region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewKMSKeyManagerWithClient(kms.New(sess), keyID)
+}
+
+// NewKMSKeyManagerWithClient is like NewKMSKeyManager but uses the given client.
+func NewKMSKeyManagerWithClient(client KMSClient, keyID string) (*KMSKeyManager, error) {
+	output, err := client.GetPublicKey(&kms.GetPublicKeyInput{KeyId: aws.String(keyID)})
+	if err != nil {
+		return nil, err
+	}
+	if keySpec := aws.StringValue(output.KeySpec); keySpec != kms.KeySpecEccSecgP256k1 {
+		return nil, fmt.Errorf("kms key %s has key spec %s, want %s", keyID, keySpec, kms.KeySpecEccSecgP256k1)
+	}
+	pubKey, err := ethsecp256k1.ParsePKIXPublicKey(output.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &KMSKeyManager{client: client, keyID: keyID, pubKey: ethsecp256k1.NewPubKey(pubKey), ecdsaKey: pubKey}, nil
+}
+
+// KMSKeyManager signs with a key which never leaves AWS KMS.
+type KMSKeyManager struct {
+	client   KMSClient
+	keyID    string
+	pubKey   *ethsecp256k1.PubKey
+	ecdsaKey *ecdsa.PublicKey
+}
+
+// Address implements keymanager.KeyManager.
+func (km *KMSKeyManager) Address() common.Address {
+	return km.pubKey.Address()
+}
+
+// Sign implements keymanager.KeyManager.
+func (km *KMSKeyManager) Sign(message []byte) (signature []byte, err error) {
+	output, err := km.client.Sign(&kms.SignInput{
+		KeyId:            aws.String(km.keyID),
+		Message:          message,
+		MessageType:      aws.String(kms.MessageTypeDigest),
+		SigningAlgorithm: aws.String(kms.SigningAlgorithmSpecEcdsaSha256),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ethsecp256k1.SignatureFromDER(message, output.Signature, km.ecdsaKey)
+}
+
+// Verify implements keymanager.KeyManager.
+func (km *KMSKeyManager) Verify(message []byte, signature []byte) (valid bool) {
+	return km.pubKey.Verify(message, signature)
+}
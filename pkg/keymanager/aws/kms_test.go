@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/keymanagertest"
+)
+
+const testKeyID = "alias/approver"
+
+// mockKMSClient signs with a local key and returns DER signatures, with a high S value from time to time like KMS may do.
+type mockKMSClient struct {
+	t       *testing.T
+	signer  *keymanagertest.Signer
+	keySpec string
+}
+
+func (c *mockKMSClient) GetPublicKey(input *kms.GetPublicKeyInput) (*kms.GetPublicKeyOutput, error) {
+	if aws.StringValue(input.KeyId) != testKeyID {
+		return nil, errors.New("NotFoundException")
+	}
+	der := keymanagertest.PublicKeyDER(c.t, &c.signer.PrivKey.PublicKey)
+	return &kms.GetPublicKeyOutput{KeyId: input.KeyId, KeySpec: aws.String(c.keySpec), PublicKey: der}, nil
+}
+
+func (c *mockKMSClient) Sign(input *kms.SignInput) (*kms.SignOutput, error) {
+	if aws.StringValue(input.MessageType) != kms.MessageTypeDigest || aws.StringValue(input.SigningAlgorithm) != kms.SigningAlgorithmSpecEcdsaSha256 {
+		return nil, errors.New("ValidationException")
+	}
+	der, err := c.signer.SignDER(input.Message)
+	if err != nil {
+		return nil, err
+	}
+	return &kms.SignOutput{KeyId: input.KeyId, Signature: der, SigningAlgorithm: input.SigningAlgorithm}, nil
+}
+
+func TestKMSKeyManager(t *testing.T) {
+	signer := keymanagertest.NewSigner(t)
+
+	if _, err := NewKMSKeyManagerWithClient(&mockKMSClient{t: t, signer: signer, keySpec: kms.KeySpecEccNistP256}, testKeyID); err == nil {
+		t.Fatal("expected error for a non secp256k1 key")
+	}
+
+	km, err := NewKMSKeyManagerWithClient(&mockKMSClient{t: t, signer: signer, keySpec: kms.KeySpecEccSecgP256k1}, testKeyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keymanagertest.CheckSigner(t, km, 4)
+}
@@ -0,0 +1,114 @@
+// Package keymanagertest provides the fixtures shared by the tests of the key managers.
+package keymanagertest
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
+)
+
+const (
+	PrivKey = "afc2986f283cf5f9d17e04c6a12ccf8fa46149fc37d48e11abef15a46ae34eb7"
+	Address = "0xb26859a7321AB7B2025E5E6a425D697e2eacbFB1"
+)
+
+// Key returns the private key of Address.
+func Key(t testing.TB) *ecdsa.PrivateKey {
+	t.Helper()
+	privKey, err := crypto.HexToECDSA(PrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return privKey
+}
+
+// PublicKeyDER returns the DER encoded SubjectPublicKeyInfo of a secp256k1 public key, as returned by KMS or vault.
+func PublicKeyDER(t testing.TB, pubKey *ecdsa.PublicKey) []byte {
+	t.Helper()
+	type algorithm struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.ObjectIdentifier
+	}
+	der, err := asn1.Marshal(struct {
+		Algorithm algorithm
+		PublicKey asn1.BitString
+	}{
+		Algorithm: algorithm{asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}, asn1.ObjectIdentifier{1, 3, 132, 0, 10}},
+		PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(pubKey), BitLength: 65 * 8},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+// Signer signs digests with the key of Address like a remote signer,
+// every other signature is returned with a high S value to exercise the normalisation of the key managers.
+type Signer struct {
+	PrivKey *ecdsa.PrivateKey
+
+	mu    sync.Mutex
+	signs int
+}
+
+// NewSigner creates a Signer of the key of Address.
+func NewSigner(t testing.TB) *Signer {
+	return &Signer{PrivKey: Key(t)}
+}
+
+// Sign returns R, S and the recovery id V of the signature of the digest.
+func (s *Signer) Sign(digest []byte) (r, sInt *big.Int, v byte, err error) {
+	sig, err := crypto.Sign(digest, s.PrivKey)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	r, sInt, v = new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), sig[crypto.RecoveryIDOffset]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.signs++; s.signs%2 == 0 {
+		sInt.Sub(crypto.S256().Params().N, sInt)
+		v ^= 1
+	}
+	return r, sInt, v, nil
+}
+
+// SignDER returns the DER encoded signature of the digest.
+func (s *Signer) SignDER(digest []byte) ([]byte, error) {
+	r, sInt, _, err := s.Sign(digest)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, sInt})
+}
+
+// CheckSigner checks the key manager holds the key of Address, and that it signs n digests
+// exactly like crypto.Sign does with the raw key.
+func CheckSigner(t testing.TB, km keymanager.KeyManager, n int) {
+	t.Helper()
+	if km.Address() != common.HexToAddress(Address) {
+		t.Fatalf("expected address %s, got %s", Address, km.Address())
+	}
+	privKey := Key(t)
+	for i := 0; i < n; i++ {
+		message := crypto.Keccak256([]byte{byte(i)})
+		signature, err := km.Sign(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := crypto.Sign(message, privKey)
+		if string(signature) != string(want) {
+			t.Fatalf("expected signature %x, got %x", want, signature)
+		}
+		if !km.Verify(message, signature) {
+			t.Fatal("expected valid signature")
+		}
+	}
+}
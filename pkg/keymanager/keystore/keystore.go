@@ -0,0 +1,124 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/crypto/ethsecp256k1"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
+)
+
+var _ keymanager.KeyManager = (*KeystoreKeyManager)(nil)
+
+var (
+	ErrDecrypt = keystore.ErrDecrypt
+	errKDFCost = errors.New("key derivation cost exceeds the limit")
+)
+
+// Upper bounds of the key derivation cost, well above the standard parameters of geth,
+// so that a crafted key file can't exhaust the CPU or the memory.
+const (
+	maxScryptN          = 1 << 20
+	maxScryptR          = 8
+	maxScryptP          = 16
+	maxPBKDF2Iterations = 10_000_000
+)
+
+// DecryptKey decrypts a Web3 Secret Storage v3 key with the passphrase once its KDF cost is checked.
+func DecryptKey(keyJSON []byte, passphrase string) (*ecdsa.PrivateKey, error) {
+	if err := checkKDFParams(keyJSON); err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return key.PrivateKey, nil
+}
+
+func checkKDFParams(keyJSON []byte) error {
+	var k struct {
+		Crypto struct {
+			KDF       string `json:"kdf"`
+			KDFParams struct {
+				N int64 `json:"n"`
+				R int64 `json:"r"`
+				P int64 `json:"p"`
+				C int64 `json:"c"`
+			} `json:"kdfparams"`
+		} `json:"crypto"`
+	}
+	if err := json.Unmarshal(keyJSON, &k); err != nil {
+		return err
+	}
+	params := k.Crypto.KDFParams
+	switch k.Crypto.KDF {
+	case "scrypt":
+		if params.N > maxScryptN || params.R > maxScryptR || params.P > maxScryptP {
+			return fmt.Errorf("%w: scrypt n=%d r=%d p=%d, max n=%d r=%d p=%d", errKDFCost, params.N, params.R, params.P, maxScryptN, maxScryptR, maxScryptP)
+		}
+	case "pbkdf2":
+		if params.C > maxPBKDF2Iterations {
+			return fmt.Errorf("%w: pbkdf2 c=%d, max c=%d", errKDFCost, params.C, maxPBKDF2Iterations)
+		}
+	}
+	return nil
+}
+
+// NewKeystoreKeyManager loads the key from a Web3 Secret Storage file and decrypts it with the passphrase.
+func NewKeystoreKeyManager(keyFile, passphrase string) (*KeystoreKeyManager, error) {
+	keyJSON, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	privKey, err := DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt keystore %s: %w", keyFile, err)
+	}
+	return &KeystoreKeyManager{ethsecp256k1.NewPrivKeyFromECDSA(privKey)}, nil
+}
+
+// ReadPassphrase reads the passphrase from passphraseFile, or from the passphraseEnv environment variable if no file is given.
+func ReadPassphrase(passphraseFile, passphraseEnv string) (string, error) {
+	if passphraseFile != "" {
+		passphrase, err := os.ReadFile(passphraseFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(passphrase), "\r\n"), nil
+	}
+	if passphraseEnv != "" {
+		passphrase, ok := os.LookupEnv(passphraseEnv)
+		if !ok {
+			return "", fmt.Errorf("passphrase env %s is not set", passphraseEnv)
+		}
+		return passphrase, nil
+	}
+	return "", errors.New("no passphrase file or env")
+}
+
+type KeystoreKeyManager struct {
+	privKey *ethsecp256k1.PrivKey
+}
+
+// Address implements keymanager.KeyManager.
+func (km *KeystoreKeyManager) Address() common.Address {
+	return km.privKey.PubKey().Address()
+}
+
+// Sign implements keymanager.KeyManager.
+func (km *KeystoreKeyManager) Sign(message []byte) (signature []byte, err error) {
+	return km.privKey.Sign(message)
+}
+
+// Verify implements keymanager.KeyManager.
+func (km *KeystoreKeyManager) Verify(message []byte, signature []byte) (valid bool) {
+	return km.privKey.PubKey().Verify(message, signature)
+}
@@ -0,0 +1,91 @@
+package keystore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// the pbkdf2 vector is from https://ethereum.org/en/developers/docs/data-structures-and-encoding/web3-secret-storage/,
+// the scrypt one is written by geth with a light scrypt cost.
+const (
+	pbkdf2KeyJSON = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+	scryptKeyJSON = `{"address":"2c7536e3605d9c16a7a3d7b1898e529396a65c23","crypto":{"cipher":"aes-128-ctr","ciphertext":"2217b28f5beeda08fdeecb156c7d00423fa728edc33befb480d41f91b29c9635","cipherparams":{"iv":"9b124975a60dd1675b4ec76dc086d772"},"kdf":"scrypt","kdfparams":{"dklen":32,"n":2,"p":1,"r":8,"salt":"4e77f788639b529f5afdc33699bb89563a1ab042aed43e5464e8f99bdf9da529"},"mac":"aaf4f41a3c9fbe54fb38bd04a940ccf92bda5c13161b9f8899f50311e38f4135"},"id":"fecfc4ce-e956-48fd-953b-30f8b52ed66c","version":3}`
+)
+
+func TestDecryptKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyJSON    string
+		passphrase string
+		wantKey    string
+		wantErr    error
+	}{
+		{"pbkdf2", pbkdf2KeyJSON, "testpassword", "0x7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d", nil},
+		{"scrypt", scryptKeyJSON, "foo", "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318", nil},
+		{"wrong passphrase", pbkdf2KeyJSON, "wrongpassword", "", ErrDecrypt},
+		{"scrypt cost", strings.Replace(scryptKeyJSON, `"n":2`, `"n":1073741824`, 1), "foo", "", errKDFCost},
+		{"pbkdf2 cost", strings.Replace(pbkdf2KeyJSON, `"c":262144`, `"c":4000000000`, 1), "testpassword", "", errKDFCost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privKey, err := DecryptKey([]byte(tt.keyJSON), tt.passphrase)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecryptKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := hexutil.Encode(crypto.FromECDSA(privKey)); got != tt.wantKey {
+				t.Errorf("DecryptKey() = %s, want %s", got, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestNewKeystoreKeyManager(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key.json")
+	if err := os.WriteFile(keyFile, []byte(scryptKeyJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	passphraseFile := filepath.Join(dir, "passphrase")
+	if err := os.WriteFile(passphraseFile, []byte("foo\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	passphrase, err := ReadPassphrase(passphraseFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	km, err := NewKeystoreKeyManager(keyFile, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privKey, _ := crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	if want := crypto.PubkeyToAddress(privKey.PublicKey); km.Address() != want {
+		t.Fatalf("expected address %s, got %s", want, km.Address())
+	}
+	message := crypto.Keccak256([]byte("hello"))
+	signature, err := km.Sign(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !km.Verify(message, signature) {
+		t.Fatal("expected valid signature")
+	}
+
+	t.Setenv("TEST_KEYSTORE_PASSPHRASE", "foo")
+	passphrase, err = ReadPassphrase("", "TEST_KEYSTORE_PASSPHRASE")
+	if err != nil || passphrase != "foo" {
+		t.Fatalf("ReadPassphrase() = %q, %v", passphrase, err)
+	}
+	if _, err := ReadPassphrase("", "TEST_KEYSTORE_PASSPHRASE_UNSET"); err == nil {
+		t.Fatal("expected error for unset passphrase env")
+	}
+}
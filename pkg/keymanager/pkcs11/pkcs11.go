@@ -0,0 +1,258 @@
+package pkcs11
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/miekg/pkcs11"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/crypto/ethsecp256k1"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
+)
+
+// oidCurveSecp256k1 is the CKA_EC_PARAMS of a secp256k1 key.
+var oidCurveSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+var _ keymanager.KeyManager = (*PKCS11KeyManager)(nil)
+
+// Config is the configuration of a PKCS11KeyManager.
+type Config struct {
+	// Module is the path of the PKCS#11 library, e.g. /usr/lib/softhsm/libsofthsm2.so
+	Module     string
+	TokenLabel string
+	PIN        string
+	// KeyLabel is the CKA_LABEL of the secp256k1 private and public key objects
+	KeyLabel string
+}
+
+// NewPKCS11KeyManager opens a logged in session on the token and looks up the secp256k1 key pair by label.
+// Key managers of the same module share its context.
+func NewPKCS11KeyManager(config Config) (*PKCS11KeyManager, error) {
+	ctx, err := openModule(config.Module)
+	if err != nil {
+		return nil, err
+	}
+	km := &PKCS11KeyManager{module: config.Module, ctx: ctx}
+	if err := km.open(config); err != nil {
+		km.Close()
+		return nil, err
+	}
+	return km, nil
+}
+
+// PKCS11KeyManager signs with a secp256k1 key which never leaves the HSM.
+type PKCS11KeyManager struct {
+	module string
+	ctx    *pkcs11.Ctx
+
+	// mu serializes the operations on the session, a session can only run one operation at a time
+	mu         sync.Mutex
+	session    pkcs11.SessionHandle
+	hasSession bool
+	privKey    pkcs11.ObjectHandle
+
+	pubKey   *ethsecp256k1.PubKey
+	ecdsaKey *ecdsa.PublicKey
+}
+
+// Address implements keymanager.KeyManager.
+func (km *PKCS11KeyManager) Address() common.Address {
+	return km.pubKey.Address()
+}
+
+// Sign implements keymanager.KeyManager.
+func (km *PKCS11KeyManager) Sign(message []byte) (signature []byte, err error) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	if err := km.ctx.SignInit(km.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, km.privKey); err != nil {
+		return nil, err
+	}
+	sig, err := km.ctx.Sign(km.session, message)
+	if err != nil {
+		return nil, err
+	}
+	// CKM_ECDSA returns the raw R || S, some tokens return a DER encoded signature instead
+	if len(sig) == 64 {
+		return ethsecp256k1.SignatureFromRS(message, new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:]), km.ecdsaKey)
+	}
+	return ethsecp256k1.SignatureFromDER(message, sig, km.ecdsaKey)
+}
+
+// Verify implements keymanager.KeyManager.
+func (km *PKCS11KeyManager) Verify(message []byte, signature []byte) (valid bool) {
+	return km.pubKey.Verify(message, signature)
+}
+
+// Close closes the session and releases the PKCS#11 module.
+// The login state is shared by all the sessions of the token, it ends with the last session.
+func (km *PKCS11KeyManager) Close() error {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	if km.ctx == nil {
+		return nil
+	}
+	var errs []error
+	if km.hasSession {
+		errs = append(errs, km.ctx.CloseSession(km.session))
+		km.hasSession = false
+	}
+	errs = append(errs, closeModule(km.module))
+	km.ctx = nil
+	return errors.Join(errs...)
+}
+
+func (km *PKCS11KeyManager) open(config Config) error {
+	slot, err := findSlot(km.ctx, config.TokenLabel)
+	if err != nil {
+		return err
+	}
+	km.session, err = km.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return err
+	}
+	km.hasSession = true
+	if err := km.ctx.Login(km.session, pkcs11.CKU_USER, config.PIN); err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+		return err
+	}
+
+	km.privKey, err = km.findObject(pkcs11.CKO_PRIVATE_KEY, config.KeyLabel)
+	if err != nil {
+		return err
+	}
+	pubKeyHandle, err := km.findObject(pkcs11.CKO_PUBLIC_KEY, config.KeyLabel)
+	if err != nil {
+		return err
+	}
+	attrs, err := km.ctx.GetAttributeValue(km.session, pubKeyHandle, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return err
+	}
+
+	var curve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(attrs[0].Value, &curve); err != nil || !curve.Equal(oidCurveSecp256k1) {
+		return fmt.Errorf("key %s is not a secp256k1 key", config.KeyLabel)
+	}
+	// CKA_EC_POINT is a DER encoded OCTET STRING, some tokens return the raw point
+	point := attrs[1].Value
+	var raw []byte
+	if rest, err := asn1.Unmarshal(point, &raw); err == nil && len(rest) == 0 {
+		point = raw
+	}
+	km.ecdsaKey, err = ethsecp256k1.UnmarshalPubKey(point)
+	if err != nil {
+		return fmt.Errorf("invalid public key %s: %w", config.KeyLabel, err)
+	}
+	km.pubKey = ethsecp256k1.NewPubKey(km.ecdsaKey)
+	return nil
+}
+
+func (km *PKCS11KeyManager) findObject(class uint, label string) (pkcs11.ObjectHandle, error) {
+	if err := km.ctx.FindObjectsInit(km.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}); err != nil {
+		return 0, err
+	}
+	objects, _, err := km.ctx.FindObjects(km.session, 2)
+	if finalErr := km.ctx.FindObjectsFinal(km.session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	switch len(objects) {
+	case 0:
+		return 0, fmt.Errorf("key %s not found", label)
+	case 1:
+		return objects[0], nil
+	default:
+		return 0, fmt.Errorf("multiple keys labeled %s", label)
+	}
+}
+
+func findSlot(ctx *pkcs11.Ctx, tokenLabel string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, err
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, err
+		}
+		if info.Label == tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("token %s not found", tokenLabel)
+}
+
+// module is a PKCS#11 module loaded once per process, C_Initialize and C_Finalize apply to the whole process.
+type module struct {
+	ctx  *pkcs11.Ctx
+	refs int
+	// owned is false when the module was initialized by another user of the process, it is then never finalized
+	owned bool
+}
+
+var (
+	modulesMu sync.Mutex
+	modules   = make(map[string]*module)
+)
+
+// openModule returns the initialized context of the module and takes a reference on it.
+func openModule(path string) (*pkcs11.Ctx, error) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	if m, ok := modules[path]; ok {
+		m.refs++
+		return m.ctx, nil
+	}
+	ctx := pkcs11.New(path)
+	if ctx == nil {
+		return nil, fmt.Errorf("load pkcs11 module %s", path)
+	}
+	owned := true
+	if err := ctx.Initialize(); err != nil {
+		if !errors.Is(err, pkcs11.Error(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED)) {
+			ctx.Destroy()
+			return nil, err
+		}
+		owned = false
+	}
+	modules[path] = &module{ctx: ctx, refs: 1, owned: owned}
+	return ctx, nil
+}
+
+// closeModule releases a reference on the module, the last one finalizes and unloads it.
+func closeModule(path string) error {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	m, ok := modules[path]
+	if !ok {
+		return fmt.Errorf("pkcs11 module %s is not open", path)
+	}
+	if m.refs--; m.refs > 0 {
+		return nil
+	}
+	delete(modules, path)
+	var err error
+	if m.owned {
+		err = m.ctx.Finalize()
+	}
+	m.ctx.Destroy()
+	return err
+}
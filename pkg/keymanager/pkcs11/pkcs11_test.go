@@ -0,0 +1,117 @@
+package pkcs11
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/miekg/pkcs11"
+)
+
+// TestPKCS11KeyManager runs against a SoftHSM token, e.g.
+//
+//	softhsm2-util --init-token --free --label approver --pin 1234 --so-pin 1234
+//	PKCS11_MODULE=/usr/lib/softhsm/libsofthsm2.so PKCS11_TOKEN_LABEL=approver PKCS11_PIN=1234 go test ./pkg/keymanager/pkcs11
+func TestPKCS11KeyManager(t *testing.T) {
+	config := Config{
+		Module:     os.Getenv("PKCS11_MODULE"),
+		TokenLabel: os.Getenv("PKCS11_TOKEN_LABEL"),
+		PIN:        os.Getenv("PKCS11_PIN"),
+		KeyLabel:   fmt.Sprintf("approver-test-%d", time.Now().UnixNano()),
+	}
+	if config.Module == "" {
+		t.Skip("PKCS11_MODULE is not set")
+	}
+	generateKeyPair(t, config)
+
+	km, err := NewPKCS11KeyManager(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer km.Close()
+
+	// a second key manager shares the module, closing it must not finalize the module of the first one
+	other, err := NewPKCS11KeyManager(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Address() != km.Address() {
+		t.Fatalf("expected address %s, got %s", km.Address(), other.Address())
+	}
+	if err := other.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 8; i++ {
+		message := crypto.Keccak256([]byte{byte(i)})
+		signature, err := km.Sign(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey, err := crypto.SigToPub(message, signature)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if crypto.PubkeyToAddress(*pubKey) != km.Address() {
+			t.Fatalf("expected signer %s, got %s", km.Address(), crypto.PubkeyToAddress(*pubKey))
+		}
+		if !crypto.ValidateSignatureValues(signature[64], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:64]), true) {
+			t.Fatalf("expected canonical low-S signature, got %x", signature)
+		}
+		if !km.Verify(message, signature) {
+			t.Fatal("expected valid signature")
+		}
+	}
+}
+
+// generateKeyPair creates a secp256k1 token key pair through the shared module context, so that the
+// session of the key manager sees it, the key pair is destroyed when the test ends.
+func generateKeyPair(t *testing.T, config Config) {
+	t.Helper()
+	ctx, err := openModule(config.Module)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slot, err := findSlot(ctx, config.TokenLabel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = ctx.CloseSession(session)
+		_ = closeModule(config.Module)
+	})
+	if err := ctx.Login(session, pkcs11.CKU_USER, config.PIN); err != nil {
+		t.Fatal(err)
+	}
+	ecParams, _ := asn1.Marshal(oidCurveSecp256k1)
+	pubKey, privKey, err := ctx.GenerateKeyPair(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, ecParams),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, config.KeyLabel),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, config.KeyLabel),
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = ctx.DestroyObject(session, pubKey)
+		_ = ctx.DestroyObject(session, privKey)
+	})
+}
@@ -0,0 +1,147 @@
+package keymanager
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrNoNextKey = errors.New("no next key to rotate to")
+
+var _ KeyManager = (*RotatingKeyManager)(nil)
+
+// Rotation describes the active key of a RotatingKeyManager and the key it rotates to.
+type Rotation struct {
+	Active     common.Address  `json:"active"`
+	Next       *common.Address `json:"next,omitempty"`
+	ActivateAt *time.Time      `json:"activate_at,omitempty"`
+}
+
+// NewRotatingKeyManager creates a key manager which signs with active until activateAt, and with next from then on.
+// A zero activateAt means next is only activated by Rotate.
+func NewRotatingKeyManager(active, next KeyManager, activateAt time.Time) *RotatingKeyManager {
+	return &RotatingKeyManager{active: active, next: next, activateAt: activateAt, now: time.Now}
+}
+
+// RotatingKeyManager holds an active key and an optional next key,
+// both keys are loaded at startup so the switch needs no restart.
+type RotatingKeyManager struct {
+	mu         sync.Mutex
+	active     KeyManager
+	next       KeyManager
+	activateAt time.Time
+	now        func() time.Time
+}
+
+// Current returns the active key, the next key is promoted once its activation time is reached.
+func (km *RotatingKeyManager) Current() KeyManager {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	if km.next != nil && !km.activateAt.IsZero() && !km.now().Before(km.activateAt) {
+		km.promote()
+	}
+	return km.active
+}
+
+// Rotate activates the next key immediately.
+func (km *RotatingKeyManager) Rotate() error {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	if km.next == nil {
+		return ErrNoNextKey
+	}
+	km.promote()
+	return nil
+}
+
+// Rotation returns the current rotation state.
+func (km *RotatingKeyManager) Rotation() Rotation {
+	active := km.Current()
+
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	rotation := Rotation{Active: active.Address()}
+	if km.next != nil {
+		next := km.next.Address()
+		rotation.Next = &next
+		if !km.activateAt.IsZero() {
+			activateAt := km.activateAt
+			rotation.ActivateAt = &activateAt
+		}
+	}
+	return rotation
+}
+
+// Address implements KeyManager.
+func (km *RotatingKeyManager) Address() common.Address {
+	return km.Current().Address()
+}
+
+// Sign implements KeyManager.
+func (km *RotatingKeyManager) Sign(message []byte) (signature []byte, err error) {
+	return km.Current().Sign(message)
+}
+
+// Verify implements KeyManager.
+func (km *RotatingKeyManager) Verify(message []byte, signature []byte) (valid bool) {
+	return km.Current().Verify(message, signature)
+}
+
+// nextKey returns the key to rotate to, nil if there is none.
+func (km *RotatingKeyManager) nextKey() KeyManager {
+	km.Current()
+
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	return km.next
+}
+
+func (km *RotatingKeyManager) promote() {
+	km.active, km.next, km.activateAt = km.next, nil, time.Time{}
+}
+
+// LoadRotations reads the rotations saved by SaveRotations, a missing file holds no rotation.
+func LoadRotations(path string) ([]Rotation, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rotations []Rotation
+	if err := json.Unmarshal(data, &rotations); err != nil {
+		return nil, err
+	}
+	return rotations, nil
+}
+
+// SaveRotations writes the rotations to a temporary file which is then renamed to path.
+func SaveRotations(path string, rotations []Rotation) error {
+	data, err := json.MarshalIndent(rotations, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,182 @@
+package keymanager
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ThresholdSigner signs a message with a set of key managers,
+// signing succeeds only if at least threshold of them sign.
+type ThresholdSigner struct {
+	kms       []KeyManager
+	threshold int
+}
+
+// NewThresholdSigner creates a ThresholdSigner, the threshold must be in [1, len(kms)].
+func NewThresholdSigner(threshold int, kms ...KeyManager) (*ThresholdSigner, error) {
+	if len(kms) == 0 {
+		return nil, errors.New("no key manager")
+	}
+	if threshold < 1 || threshold > len(kms) {
+		return nil, fmt.Errorf("invalid threshold %d of %d signers", threshold, len(kms))
+	}
+	addresses := make([]common.Address, 0, len(kms))
+	for _, km := range kms {
+		addresses = append(addresses, km.Address())
+	}
+	if err := checkDuplicates(addresses); err != nil {
+		return nil, err
+	}
+	if err := checkNextKeys(kms); err != nil {
+		return nil, err
+	}
+	return &ThresholdSigner{kms: kms, threshold: threshold}, nil
+}
+
+// checkNextKeys checks the next keys differ from the keys of the other signers, a next key activated
+// at its activation time is not checked again and would otherwise sign twice.
+func checkNextKeys(kms []KeyManager) error {
+	signers := make(map[common.Address]int, len(kms))
+	for i, km := range kms {
+		signers[km.Address()] = i
+	}
+	for i, km := range kms {
+		rotating, ok := km.(*RotatingKeyManager)
+		if !ok {
+			continue
+		}
+		next := rotating.nextKey()
+		if next == nil {
+			continue
+		}
+		if signer, ok := signers[next.Address()]; ok && signer != i {
+			return fmt.Errorf("duplicate signer %s, next key of signer %d is a key of signer %d", next.Address(), i, signer)
+		}
+		signers[next.Address()] = i
+	}
+	return nil
+}
+
+func checkDuplicates(addresses []common.Address) error {
+	seen := make(map[common.Address]struct{}, len(addresses))
+	for _, address := range addresses {
+		if _, ok := seen[address]; ok {
+			return fmt.Errorf("duplicate signer %s", address)
+		}
+		seen[address] = struct{}{}
+	}
+	return nil
+}
+
+// Threshold returns the minimum number of signatures.
+func (s *ThresholdSigner) Threshold() int {
+	return s.threshold
+}
+
+// Addresses returns the addresses of all signers.
+func (s *ThresholdSigner) Addresses() []common.Address {
+	addresses := make([]common.Address, 0, len(s.kms))
+	for _, km := range s.kms {
+		addresses = append(addresses, km.Address())
+	}
+	return addresses
+}
+
+// Sign signs the message with every signer, it returns the signatures and the addresses of the signers which succeeded.
+func (s *ThresholdSigner) Sign(message []byte) (signatures [][]byte, signers []common.Address, err error) {
+	var errs []error
+	for _, km := range s.kms {
+		// pin the active key of a rotating key manager so the signature and address match
+		if rotating, ok := km.(*RotatingKeyManager); ok {
+			km = rotating.Current()
+		}
+		signature, err := km.Sign(message)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("signer %s: %w", km.Address(), err))
+			continue
+		}
+		signatures = append(signatures, signature)
+		signers = append(signers, km.Address())
+	}
+	if len(signatures) < s.threshold {
+		return nil, nil, fmt.Errorf("%d of %d signatures are below threshold %d: %w", len(signatures), len(s.kms), s.threshold, errors.Join(errs...))
+	}
+	return signatures, signers, nil
+}
+
+// Verify reports whether the signature of the message is signed by the signer.
+func (s *ThresholdSigner) Verify(signer common.Address, message []byte, signature []byte) bool {
+	for _, km := range s.kms {
+		if km.Address() == signer {
+			return km.Verify(message, signature)
+		}
+	}
+	return false
+}
+
+// Rotate activates the next key of every rotating signer which has one.
+func (s *ThresholdSigner) Rotate() ([]Rotation, error) {
+	if err := s.rotate(func(common.Address) bool { return true }); err != nil {
+		return nil, err
+	}
+	return s.Rotations(), nil
+}
+
+// Restore activates the next keys which are active in the saved rotations, so that a rotation done by Rotate
+// survives a restart.
+func (s *ThresholdSigner) Restore(rotations []Rotation) error {
+	active := make(map[common.Address]struct{}, len(rotations))
+	for _, rotation := range rotations {
+		active[rotation.Active] = struct{}{}
+	}
+	err := s.rotate(func(next common.Address) bool {
+		_, ok := active[next]
+		return ok
+	})
+	if errors.Is(err, ErrNoNextKey) {
+		return nil
+	}
+	return err
+}
+
+// rotate activates the next keys selected by the filter, the signers must stay distinct once they are activated.
+func (s *ThresholdSigner) rotate(filter func(next common.Address) bool) error {
+	var rotating []*RotatingKeyManager
+	addresses := make([]common.Address, 0, len(s.kms))
+	for _, km := range s.kms {
+		if r, ok := km.(*RotatingKeyManager); ok {
+			if next := r.nextKey(); next != nil && filter(next.Address()) {
+				rotating = append(rotating, r)
+				addresses = append(addresses, next.Address())
+				continue
+			}
+		}
+		addresses = append(addresses, km.Address())
+	}
+	if len(rotating) == 0 {
+		return ErrNoNextKey
+	}
+	if err := checkDuplicates(addresses); err != nil {
+		return err
+	}
+	for _, r := range rotating {
+		// the next key may have been activated by its activation time meanwhile
+		_ = r.Rotate()
+	}
+	return nil
+}
+
+// Rotations returns the rotation state of every signer.
+func (s *ThresholdSigner) Rotations() []Rotation {
+	rotations := make([]Rotation, 0, len(s.kms))
+	for _, km := range s.kms {
+		if rotating, ok := km.(*RotatingKeyManager); ok {
+			rotations = append(rotations, rotating.Rotation())
+			continue
+		}
+		rotations = append(rotations, Rotation{Active: km.Address()})
+	}
+	return rotations
+}
@@ -0,0 +1,80 @@
+package keymanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// addressKeyManager signs every message with its address.
+type addressKeyManager common.Address
+
+func (km addressKeyManager) Address() common.Address {
+	return common.Address(km)
+}
+
+func (km addressKeyManager) Sign(message []byte) ([]byte, error) {
+	return km.Address().Bytes(), nil
+}
+
+func (km addressKeyManager) Verify(message []byte, signature []byte) bool {
+	return string(signature) == string(km.Address().Bytes())
+}
+
+func TestNewThresholdSigner_NextKeys(t *testing.T) {
+	a, b, c, d := addressKeyManager{1}, addressKeyManager{2}, addressKeyManager{3}, addressKeyManager{4}
+	activateAt := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	clock := activateAt.Add(-time.Hour)
+	rotating := func(active, next KeyManager) *RotatingKeyManager {
+		km := NewRotatingKeyManager(active, next, activateAt)
+		km.now = func() time.Time { return clock }
+		return km
+	}
+
+	tests := []struct {
+		name    string
+		kms     []KeyManager
+		wantErr bool
+	}{
+		{"next key of another active signer", []KeyManager{a, rotating(b, a)}, true},
+		{"next key of another rotating signer", []KeyManager{rotating(a, c), rotating(b, a)}, true},
+		{"same next key twice", []KeyManager{rotating(a, c), rotating(b, c)}, true},
+		{"next key of the same signer", []KeyManager{a, rotating(b, b)}, false},
+		{"next key of a later signer", []KeyManager{a, rotating(b, c), rotating(c, d)}, true},
+		{"distinct keys", []KeyManager{a, rotating(b, c)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewThresholdSigner(2, tt.kms...); (err != nil) != tt.wantErr {
+				t.Fatalf("NewThresholdSigner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// past the activation time every next key is active and signs once
+	clock = activateAt.Add(-time.Hour)
+	signer, err := NewThresholdSigner(3, a, rotating(b, c), rotating(d, addressKeyManager{5}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock = activateAt.Add(time.Second)
+	signatures, signers, err := signer.Sign([]byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []common.Address{a.Address(), c.Address(), {5}}
+	if len(signatures) != 3 || len(signers) != len(want) {
+		t.Fatalf("got %d signatures of %v, want %v", len(signatures), signers, want)
+	}
+	for i := range want {
+		if signers[i] != want[i] {
+			t.Errorf("signer %d is %s, want %s", i, signers[i], want[i])
+		}
+	}
+
+	// a next key already activated at startup is checked as an active key
+	if _, err := NewThresholdSigner(2, a, rotating(b, a)); err == nil {
+		t.Fatal("expected an error for an activated next key which is already a signer")
+	}
+}
@@ -0,0 +1,82 @@
+package unixsocket
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/crypto/ethsecp256k1"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
+)
+
+var _ keymanager.KeyManager = (*UnixSocketKeyManager)(nil)
+
+// NewUnixSocketKeyManager creates a key manager which asks the signer daemon on the socket at path to sign,
+// the address is read once at startup.
+func NewUnixSocketKeyManager(path string, timeout time.Duration) (*UnixSocketKeyManager, error) {
+	km := &UnixSocketKeyManager{path: path, timeout: timeout}
+	data, err := km.call(opAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != common.AddressLength {
+		return nil, fmt.Errorf("invalid signer address length %d", len(data))
+	}
+	km.address = common.BytesToAddress(data)
+	return km, nil
+}
+
+type UnixSocketKeyManager struct {
+	path    string
+	timeout time.Duration
+	address common.Address
+}
+
+// Address implements keymanager.KeyManager.
+func (km *UnixSocketKeyManager) Address() common.Address {
+	return km.address
+}
+
+// Sign implements keymanager.KeyManager.
+func (km *UnixSocketKeyManager) Sign(message []byte) (signature []byte, err error) {
+	signature, err = km.call(opSign, message)
+	if err != nil {
+		return nil, err
+	}
+	// the daemon is trusted with the key but not with the signature, check it matches the address
+	if !km.Verify(message, signature) {
+		return nil, errors.New("signer daemon returned an invalid signature")
+	}
+	return signature, nil
+}
+
+// Verify implements keymanager.KeyManager.
+func (km *UnixSocketKeyManager) Verify(message []byte, signature []byte) (valid bool) {
+	return ethsecp256k1.VerifyAddress(km.address, message, signature)
+}
+
+func (km *UnixSocketKeyManager) call(op byte, data []byte) ([]byte, error) {
+	conn, err := net.DialTimeout("unix", km.path, km.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if km.timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(km.timeout))
+	}
+
+	if err := writeFrame(conn, op, data); err != nil {
+		return nil, err
+	}
+	status, result, err := readFrame(conn)
+	if err != nil {
+		return nil, err
+	}
+	if status != statusOK {
+		return nil, fmt.Errorf("signer daemon: %s", result)
+	}
+	return result, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package unixsocket
+
+import (
+	"errors"
+	"net"
+)
+
+// peerUID is only supported on linux, the server refuses every connection if allowed uids are configured.
+func peerUID(_ *net.UnixConn) (uint32, error) {
+	return 0, errors.New("peer credentials are not supported on this platform")
+}
@@ -0,0 +1,52 @@
+package unixsocket
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// A frame is a 4-byte big-endian payload length followed by the payload.
+// The first payload byte of a request is the operation and of a response the status,
+// the rest is the operation argument or the result.
+const (
+	opAddress byte = 0x01
+	opSign    byte = 0x02
+
+	statusOK    byte = 0x00
+	statusError byte = 0x01
+
+	maxFrameSize = 4096
+	digestLength = 32
+)
+
+var errFrameTooLarge = errors.New("frame too large")
+
+func writeFrame(w io.Writer, kind byte, data []byte) error {
+	frame := make([]byte, 5+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(1+len(data)))
+	frame[4] = kind
+	copy(frame[5:], data)
+	_, err := w.Write(frame)
+	return err
+}
+
+func readFrame(r io.Reader) (kind byte, data []byte, err error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if size == 0 {
+		return 0, nil, fmt.Errorf("empty frame")
+	}
+	if size > maxFrameSize {
+		return 0, nil, errFrameTooLarge
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return payload[0], payload[1:], nil
+}
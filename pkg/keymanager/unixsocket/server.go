@@ -0,0 +1,153 @@
+package unixsocket
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
+)
+
+// NewServer creates a server which signs digests with km for the peers on the socket at path.
+// The socket file is created with mode, and if allowedUIDs is not empty only peers running as one of them are served.
+func NewServer(km keymanager.KeyManager, path string, mode os.FileMode, allowedUIDs []uint32, timeout time.Duration, logger *zerolog.Logger) *Server {
+	uids := make(map[uint32]struct{}, len(allowedUIDs))
+	for _, uid := range allowedUIDs {
+		uids[uid] = struct{}{}
+	}
+	return &Server{km: km, path: path, mode: mode, allowedUIDs: uids, timeout: timeout, logger: logger}
+}
+
+type Server struct {
+	km          keymanager.KeyManager
+	path        string
+	mode        os.FileMode
+	allowedUIDs map[uint32]struct{}
+	timeout     time.Duration
+	logger      *zerolog.Logger
+
+	mu       sync.Mutex
+	listener *net.UnixListener
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// ListenAndServe listens on the socket and serves until Close is called.
+func (s *Server) ListenAndServe() error {
+	// a stale socket of a previous run would make listen fail
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: s.path, Net: "unix"})
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(s.path, s.mode); err != nil {
+		listener.Close()
+		return err
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		listener.Close()
+		return nil
+	}
+	s.listener = listener
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.AcceptUnix()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serve(conn)
+		}()
+	}
+}
+
+// Close stops accepting connections and waits for the served ones.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	listener := s.listener
+	s.mu.Unlock()
+	if listener == nil {
+		return nil
+	}
+	err := listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve(conn *net.UnixConn) {
+	defer conn.Close()
+
+	if len(s.allowedUIDs) > 0 {
+		uid, err := peerUID(conn)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("read peer credentials")
+			_ = writeFrame(conn, statusError, []byte("permission denied"))
+			return
+		}
+		if _, ok := s.allowedUIDs[uid]; !ok {
+			s.logger.Warn().Uint32("uid", uid).Msg("peer is not allowed")
+			_ = writeFrame(conn, statusError, []byte("permission denied"))
+			return
+		}
+	}
+
+	for {
+		if s.timeout > 0 {
+			_ = conn.SetDeadline(time.Now().Add(s.timeout))
+		}
+		op, data, err := readFrame(conn)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				s.logger.Debug().Err(err).Msg("read request")
+			}
+			return
+		}
+		result, err := s.handle(op, data)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("handle request")
+			if err := writeFrame(conn, statusError, []byte(err.Error())); err != nil {
+				return
+			}
+			continue
+		}
+		if err := writeFrame(conn, statusOK, result); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handle(op byte, data []byte) ([]byte, error) {
+	switch op {
+	case opAddress:
+		return s.km.Address().Bytes(), nil
+	case opSign:
+		// only digests are signed so the socket can not be used to sign arbitrary payloads
+		if len(data) != digestLength {
+			return nil, fmt.Errorf("invalid digest length %d", len(data))
+		}
+		return s.km.Sign(data)
+	default:
+		return nil, fmt.Errorf("unknown operation %#x", op)
+	}
+}
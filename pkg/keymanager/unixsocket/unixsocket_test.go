@@ -0,0 +1,81 @@
+package unixsocket
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/keymanagertest"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/local"
+)
+
+func startServer(t *testing.T, allowedUIDs []uint32) string {
+	t.Helper()
+	km, err := local.NewLocalKeyManager(keymanagertest.PrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "signer.sock")
+	server := NewServer(km, path, 0o600, allowedUIDs, time.Second, &zerolog.Logger{})
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.ListenAndServe() }()
+	t.Cleanup(func() {
+		if err := server.Close(); err != nil {
+			t.Error(err)
+		}
+		if err := <-errCh; err != nil {
+			t.Error(err)
+		}
+	})
+
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("signer socket is not created")
+	return ""
+}
+
+func TestUnixSocketKeyManager(t *testing.T) {
+	path := startServer(t, []uint32{uint32(os.Getuid())})
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("expected socket mode 0600, got %o", perm)
+	}
+
+	km, err := NewUnixSocketKeyManager(path, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := local.NewLocalKeyManager(keymanagertest.PrivKey)
+	if km.Address() != want.Address() {
+		t.Fatalf("expected address %s, got %s", want.Address(), km.Address())
+	}
+	message := crypto.Keccak256([]byte("hello"))
+	signature, err := km.Sign(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !km.Verify(message, signature) {
+		t.Fatal("expected valid signature")
+	}
+	if _, err := km.Sign([]byte("not a digest")); err == nil {
+		t.Fatal("expected error for a message which is not a digest")
+	}
+}
+
+func TestUnixSocketKeyManager_PeerNotAllowed(t *testing.T) {
+	path := startServer(t, []uint32{uint32(os.Getuid()) + 1})
+	if _, err := NewUnixSocketKeyManager(path, time.Second); err == nil {
+		t.Fatal("expected error for a peer which is not allowed")
+	}
+}
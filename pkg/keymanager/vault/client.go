@@ -0,0 +1,91 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const tokenHeader = "X-Vault-Token"
+
+type errorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+type authResponse struct {
+	Auth struct {
+		ClientToken string `json:"client_token"`
+	} `json:"auth"`
+}
+
+type keyResponse struct {
+	Data struct {
+		LatestVersion int `json:"latest_version"`
+		Keys          map[string]struct {
+			PublicKey string `json:"public_key"`
+		} `json:"keys"`
+	} `json:"data"`
+}
+
+type signRequest struct {
+	Input               string `json:"input"`
+	KeyVersion          int    `json:"key_version"`
+	Prehashed           bool   `json:"prehashed"`
+	MarshalingAlgorithm string `json:"marshaling_algorithm"`
+}
+
+type signResponse struct {
+	Data struct {
+		Signature string `json:"signature"`
+	} `json:"data"`
+}
+
+// StatusError is returned when vault responds with a non 2xx status.
+type StatusError struct {
+	StatusCode int
+	Errors     []string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("vault responded %d: %s", e.StatusCode, strings.Join(e.Errors, "; "))
+}
+
+func (km *VaultKeyManager) do(method, path string, token string, reqBody any, respBody any) error {
+	var body io.Reader
+	if reqBody != nil {
+		data, err := json.Marshal(reqBody)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, km.config.Address+"/v1/"+path, body)
+	if err != nil {
+		return err
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set(tokenHeader, token)
+	}
+	if km.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", km.config.Namespace)
+	}
+
+	resp, err := km.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp errorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return &StatusError{StatusCode: resp.StatusCode, Errors: errResp.Errors}
+	}
+	return json.NewDecoder(resp.Body).Decode(respBody)
+}
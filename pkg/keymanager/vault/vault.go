@@ -0,0 +1,178 @@
+package vault
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/crypto/ethsecp256k1"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
+)
+
+var _ keymanager.KeyManager = (*VaultKeyManager)(nil)
+
+// Config is the configuration of a VaultKeyManager, either Token or AppRole credentials must be set.
+type Config struct {
+	Address   string
+	Namespace string
+	Token     string
+	// AppRole login is used if Token is empty
+	AppRoleMountPath string
+	RoleID           string
+	SecretID         string
+	// MountPath is the mount path of the transit secrets engine
+	MountPath string
+	KeyName   string
+	Timeout   time.Duration
+}
+
+// NewVaultKeyManager creates a key manager signing with a secp256k1 key held by a vault transit secrets engine,
+// the public key of the latest key version is fetched once and every signature is made with that version.
+func NewVaultKeyManager(config Config) (*VaultKeyManager, error) {
+	if config.KeyName == "" {
+		return nil, errors.New("vault key name is empty")
+	}
+	if config.Token == "" && (config.RoleID == "" || config.SecretID == "") {
+		return nil, errors.New("vault token or approle credentials are required")
+	}
+	config.Address = strings.TrimSuffix(config.Address, "/")
+	if config.MountPath == "" {
+		config.MountPath = "transit"
+	}
+	if config.AppRoleMountPath == "" {
+		config.AppRoleMountPath = "approle"
+	}
+
+	km := &VaultKeyManager{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		token:  config.Token,
+	}
+	if km.token == "" {
+		if err := km.login(); err != nil {
+			return nil, err
+		}
+	}
+	if err := km.loadPublicKey(); err != nil {
+		return nil, err
+	}
+	return km, nil
+}
+
+type VaultKeyManager struct {
+	config Config
+	client *http.Client
+
+	mu    sync.RWMutex
+	token string
+
+	keyVersion int
+	pubKey     *ethsecp256k1.PubKey
+	ecdsaKey   *ecdsa.PublicKey
+}
+
+// Address implements keymanager.KeyManager.
+func (km *VaultKeyManager) Address() common.Address {
+	return km.pubKey.Address()
+}
+
+// Sign implements keymanager.KeyManager.
+func (km *VaultKeyManager) Sign(message []byte) (signature []byte, err error) {
+	req := &signRequest{
+		Input:               base64.StdEncoding.EncodeToString(message),
+		KeyVersion:          km.keyVersion,
+		Prehashed:           true,
+		MarshalingAlgorithm: "asn1",
+	}
+	var resp signResponse
+	if err := km.withToken(func(token string) error {
+		return km.do(http.MethodPost, km.config.MountPath+"/sign/"+km.config.KeyName, token, req, &resp)
+	}); err != nil {
+		return nil, err
+	}
+
+	// the signature is formatted as vault:v<version>:<base64 signature>
+	parts := strings.Split(resp.Data.Signature, ":")
+	der, err := base64.StdEncoding.DecodeString(parts[len(parts)-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault signature: %w", err)
+	}
+	return ethsecp256k1.SignatureFromDER(message, der, km.ecdsaKey)
+}
+
+// Verify implements keymanager.KeyManager.
+func (km *VaultKeyManager) Verify(message []byte, signature []byte) (valid bool) {
+	return km.pubKey.Verify(message, signature)
+}
+
+func (km *VaultKeyManager) login() error {
+	var resp authResponse
+	if err := km.do(http.MethodPost, "auth/"+km.config.AppRoleMountPath+"/login", "", map[string]string{
+		"role_id":   km.config.RoleID,
+		"secret_id": km.config.SecretID,
+	}, &resp); err != nil {
+		return fmt.Errorf("vault approle login: %w", err)
+	}
+	if resp.Auth.ClientToken == "" {
+		return errors.New("vault approle login returned no token")
+	}
+	km.mu.Lock()
+	km.token = resp.Auth.ClientToken
+	km.mu.Unlock()
+	return nil
+}
+
+// withToken calls fn with the current token, the approle login is renewed once if the token is rejected.
+func (km *VaultKeyManager) withToken(fn func(token string) error) error {
+	km.mu.RLock()
+	token := km.token
+	km.mu.RUnlock()
+
+	err := fn(token)
+	var statusErr *StatusError
+	if km.config.Token != "" || !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+		return err
+	}
+	if err := km.login(); err != nil {
+		return err
+	}
+	km.mu.RLock()
+	token = km.token
+	km.mu.RUnlock()
+	return fn(token)
+}
+
+func (km *VaultKeyManager) loadPublicKey() error {
+	var resp keyResponse
+	if err := km.withToken(func(token string) error {
+		return km.do(http.MethodGet, km.config.MountPath+"/keys/"+km.config.KeyName, token, nil, &resp)
+	}); err != nil {
+		return fmt.Errorf("vault read key %s: %w", km.config.KeyName, err)
+	}
+
+	key, ok := resp.Data.Keys[strconv.Itoa(resp.Data.LatestVersion)]
+	if !ok {
+		return fmt.Errorf("vault key %s has no version %d", km.config.KeyName, resp.Data.LatestVersion)
+	}
+	block, _ := pem.Decode([]byte(key.PublicKey))
+	if block == nil {
+		return fmt.Errorf("vault key %s has no PEM public key", km.config.KeyName)
+	}
+	pubKey, err := ethsecp256k1.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+	km.keyVersion = resp.Data.LatestVersion
+	km.ecdsaKey = pubKey
+	km.pubKey = ethsecp256k1.NewPubKey(pubKey)
+	return nil
+}
@@ -0,0 +1,95 @@
+package vault
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/keymanagertest"
+)
+
+const testToken = "s.test-token"
+
+// newTransitStandIn serves the subset of the vault transit and approle API used by VaultKeyManager,
+// signatures are returned with a high S value from time to time to exercise the normalisation.
+func newTransitStandIn(t *testing.T, signer *keymanagertest.Signer) *httptest.Server {
+	t.Helper()
+	pubKeyDER := keymanagertest.PublicKeyDER(t, &signer.PrivKey.PublicKey)
+	pubKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubKeyDER})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req["role_id"] != "role" || req["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"` + testToken + `"}}`))
+	})
+	mux.HandleFunc("/v1/transit/keys/approver", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(tokenHeader) != testToken {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"latest_version": 2,
+			"keys":           map[string]any{"2": map[string]string{"public_key": string(pubKeyPEM)}},
+		}})
+	})
+	mux.HandleFunc("/v1/transit/sign/approver", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(tokenHeader) != testToken {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		var req signRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !req.Prehashed || req.KeyVersion != 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		digest, _ := base64.StdEncoding.DecodeString(req.Input)
+		der, err := signer.SignDER(digest)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"signature":"vault:v2:` + base64.StdEncoding.EncodeToString(der) + `"}}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestVaultKeyManager(t *testing.T) {
+	server := newTransitStandIn(t, keymanagertest.NewSigner(t))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{"token", Config{Address: server.URL, Token: testToken, KeyName: "approver", Timeout: time.Second}, false},
+		{"approle", Config{Address: server.URL, RoleID: "role", SecretID: "secret", KeyName: "approver", Timeout: time.Second}, false},
+		{"invalid token", Config{Address: server.URL, Token: "invalid", KeyName: "approver", Timeout: time.Second}, true},
+		{"invalid approle", Config{Address: server.URL, RoleID: "role", SecretID: "invalid", KeyName: "approver", Timeout: time.Second}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			km, err := NewVaultKeyManager(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewVaultKeyManager() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			// the signatures must be identical to the ones produced with the raw key
+			keymanagertest.CheckSigner(t, km, 4)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package web3signer
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/crypto/ethsecp256k1"
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager"
+)
+
+const (
+	publicKeysPath = "/api/v1/eth1/publicKeys"
+	signPath       = "/api/v1/eth1/sign/"
+)
+
+var _ keymanager.KeyManager = (*Web3SignerKeyManager)(nil)
+
+// Config is the configuration of a Web3SignerKeyManager.
+type Config struct {
+	URL string
+	// Address is the pinned approver address, the remote signer must hold its key
+	Address common.Address
+	// CACert, ClientCert and ClientKey are PEM files used for mutual TLS, all optional
+	CACert     string
+	ClientCert string
+	ClientKey  string
+	// DialTimeout is the connect timeout and Timeout the timeout of a whole request
+	DialTimeout time.Duration
+	Timeout     time.Duration
+}
+
+// NewWeb3SignerKeyManager creates a key manager forwarding digests to a remote signer serving the routes
+// of the Web3Signer eth1 API. The remote signer must sign the 32-byte digest as is: a stock Web3Signer
+// keccak256-hashes the data before signing and is not supported, its signatures are rejected by Sign.
+// The public key of the pinned address is looked up once at startup.
+func NewWeb3SignerKeyManager(config Config) (*Web3SignerKeyManager, error) {
+	if config.Address == (common.Address{}) {
+		return nil, errors.New("web3signer address is not pinned")
+	}
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	km := &Web3SignerKeyManager{
+		url: strings.TrimSuffix(config.URL, "/"),
+		client: &http.Client{
+			Timeout: config.Timeout,
+			Transport: &http.Transport{
+				Proxy:               http.ProxyFromEnvironment,
+				DialContext:         (&net.Dialer{Timeout: config.DialTimeout}).DialContext,
+				TLSClientConfig:     tlsConfig,
+				TLSHandshakeTimeout: config.DialTimeout,
+			},
+		},
+	}
+
+	pubKey, identifier, err := km.lookupPublicKey(config.Address)
+	if err != nil {
+		return nil, err
+	}
+	km.ecdsaKey = pubKey
+	km.pubKey = ethsecp256k1.NewPubKey(pubKey)
+	km.identifier = identifier
+	return km, nil
+}
+
+type Web3SignerKeyManager struct {
+	url    string
+	client *http.Client
+
+	identifier string
+	pubKey     *ethsecp256k1.PubKey
+	ecdsaKey   *ecdsa.PublicKey
+}
+
+// Address implements keymanager.KeyManager.
+func (km *Web3SignerKeyManager) Address() common.Address {
+	return km.pubKey.Address()
+}
+
+// Sign implements keymanager.KeyManager.
+func (km *Web3SignerKeyManager) Sign(message []byte) (signature []byte, err error) {
+	body, err := json.Marshal(map[string]string{"data": hexutil.Encode(message)})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := km.client.Post(km.url+signPath+km.identifier, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := hexutil.Decode(strings.Trim(strings.TrimSpace(string(data)), `"`))
+	if err != nil {
+		return nil, fmt.Errorf("invalid web3signer signature: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid web3signer signature length %d", len(sig))
+	}
+	// the remote V may be 0/1 or 27/28, it is recomputed against the cached public key
+	signature, err = ethsecp256k1.SignatureFromRS(message, new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), km.ecdsaKey)
+	if err != nil {
+		return nil, fmt.Errorf("web3signer signature is not over the digest, the remote signer must not hash the data: %w", err)
+	}
+	return signature, nil
+}
+
+// Verify implements keymanager.KeyManager.
+func (km *Web3SignerKeyManager) Verify(message []byte, signature []byte) (valid bool) {
+	return km.pubKey.Verify(message, signature)
+}
+
+// lookupPublicKey returns the public key of the address with its identifier, the string listed by the
+// remote signer which is used as is in the sign path.
+func (km *Web3SignerKeyManager) lookupPublicKey(address common.Address) (*ecdsa.PublicKey, string, error) {
+	resp, err := km.client.Get(km.url + publicKeysPath)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	data, err := readBody(resp)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var pubKeys []string
+	if err := json.Unmarshal(data, &pubKeys); err != nil {
+		return nil, "", fmt.Errorf("invalid web3signer public keys: %w", err)
+	}
+	for _, pubKey := range pubKeys {
+		raw, err := hexutil.Decode(pubKey)
+		if err != nil {
+			continue
+		}
+		// web3signer returns the 64-byte public key without the 0x04 prefix
+		if len(raw) == 64 {
+			raw = append([]byte{0x04}, raw...)
+		}
+		key, err := ethsecp256k1.UnmarshalPubKey(raw)
+		if err != nil {
+			continue
+		}
+		if crypto.PubkeyToAddress(*key) == address {
+			return key, pubKey, nil
+		}
+	}
+	return nil, "", fmt.Errorf("web3signer does not hold the key of %s", address)
+}
+
+func readBody(resp *http.Response) ([]byte, error) {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("web3signer responded %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+func newTLSConfig(config Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.CACert != "" {
+		caCert, err := os.ReadFile(config.CACert)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificate found in %s", config.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if config.ClientCert != "" || config.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(config.ClientCert, config.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
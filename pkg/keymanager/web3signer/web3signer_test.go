@@ -0,0 +1,183 @@
+package web3signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/keymanager/keymanagertest"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signerCert, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+		signerCert, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// newRemoteSigner serves the Web3Signer eth1 endpoints over mutual TLS, signatures are returned with V in 27/28.
+// With hashData the data is keccak256-hashed before signing like a stock Web3Signer does.
+func newRemoteSigner(t *testing.T, signer *keymanagertest.Signer, ca, server *testCert, hashData bool) *httptest.Server {
+	t.Helper()
+	pubKey := hexutil.Encode(crypto.FromECDSAPub(&signer.PrivKey.PublicKey)[1:])
+	mux := http.NewServeMux()
+	mux.HandleFunc(publicKeysPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]string{hexutil.Encode(make([]byte, 64)), pubKey})
+	})
+	mux.HandleFunc(signPath, func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimPrefix(r.URL.Path, signPath) != pubKey {
+			http.Error(w, "Public Key not found", http.StatusNotFound)
+			return
+		}
+		var req struct {
+			Data string `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data := hexutil.MustDecode(req.Data)
+		if hashData {
+			data = crypto.Keccak256(data)
+		}
+		rInt, sInt, v, err := signer.Sign(data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sig := append(append(math.PaddedBigBytes(rInt, 32), math.PaddedBigBytes(sInt, 32)...), v+27)
+		_, _ = w.Write([]byte(hexutil.Encode(sig)))
+	})
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	tlsCert, err := tls.X509KeyPair(server.certPEM, server.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewUnstartedServer(mux)
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{tlsCert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	srv.StartTLS()
+	return srv
+}
+
+func TestWeb3SignerKeyManager(t *testing.T) {
+	signer := keymanagertest.NewSigner(t)
+	ca := newTestCert(t, "ca", nil, 0)
+	serverCert := newTestCert(t, "server", ca, x509.ExtKeyUsageServerAuth)
+	clientCert := newTestCert(t, "client", ca, x509.ExtKeyUsageClientAuth)
+	server := newRemoteSigner(t, signer, ca, serverCert, false)
+	defer server.Close()
+
+	dir := t.TempDir()
+	config := Config{
+		URL:         server.URL,
+		Address:     common.HexToAddress(keymanagertest.Address),
+		CACert:      writeFile(t, dir, "ca.pem", ca.certPEM),
+		ClientCert:  writeFile(t, dir, "client.pem", clientCert.certPEM),
+		ClientKey:   writeFile(t, dir, "client.key", clientCert.keyPEM),
+		DialTimeout: time.Second,
+		Timeout:     time.Second,
+	}
+
+	km, err := NewWeb3SignerKeyManager(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keymanagertest.CheckSigner(t, km, 4)
+
+	// the pinned address must be held by the remote signer
+	pinned := config
+	pinned.Address = common.HexToAddress("0x2e9247B67ae885a8dcfBf77Eb6d0e93A32bea24C")
+	if _, err := NewWeb3SignerKeyManager(pinned); err == nil {
+		t.Fatal("expected error for an address not held by the remote signer")
+	}
+
+	// the remote signer requires a client certificate
+	noClientCert := config
+	noClientCert.ClientCert, noClientCert.ClientKey = "", ""
+	if _, err := NewWeb3SignerKeyManager(noClientCert); err == nil {
+		t.Fatal("expected error without client certificate")
+	}
+
+	// a remote signer hashing the digest again signs another message
+	hashing := newRemoteSigner(t, signer, ca, serverCert, true)
+	defer hashing.Close()
+	hashingConfig := config
+	hashingConfig.URL = hashing.URL
+	km, err = NewWeb3SignerKeyManager(hashingConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := km.Sign(crypto.Keccak256([]byte("hello"))); err == nil {
+		t.Fatal("expected error for a remote signer hashing the digest")
+	}
+}
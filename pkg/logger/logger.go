@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -22,6 +23,11 @@ const (
 
 // NewLogger returns a zerolog.Logger
 func NewLogger(logLevel string, logFormat LogFormat, opts ...Option) (*zerolog.Logger, error) {
+	return NewLoggerWithWriter(logLevel, logFormat, os.Stdout, opts...)
+}
+
+// NewLoggerWithWriter returns a zerolog.Logger which writes to w
+func NewLoggerWithWriter(logLevel string, logFormat LogFormat, w io.Writer, opts ...Option) (*zerolog.Logger, error) {
 	level, err := zerolog.ParseLevel(strings.ToLower(logLevel))
 	if err != nil {
 		return &zerolog.Logger{}, err
@@ -33,9 +39,9 @@ func NewLogger(logLevel string, logFormat LogFormat, opts ...Option) (*zerolog.L
 
 	switch logFormat {
 	case JSONFormat:
-		log = zerolog.New(os.Stdout).With().Caller().Timestamp().Logger()
+		log = zerolog.New(w).With().Caller().Timestamp().Logger()
 	case ConsoleFormat:
-		log = zerolog.New(os.Stdout).With().Caller().Timestamp().Logger().Output(zerolog.ConsoleWriter{TimeFormat: time.RFC3339Nano, Out: os.Stdout})
+		log = zerolog.New(w).With().Caller().Timestamp().Logger().Output(zerolog.ConsoleWriter{TimeFormat: time.RFC3339Nano, Out: w})
 	default:
 		err = fmt.Errorf("not support log format [%s]", logFormat)
 	}
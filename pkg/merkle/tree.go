@@ -0,0 +1,94 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrEmptyTree     = errors.New("merkle tree has no leaves")
+	ErrDuplicateLeaf = errors.New("duplicate merkle leaf")
+	ErrLeafNotFound  = errors.New("merkle leaf not found")
+)
+
+// Tree is a merkle tree whose parents are the keccak256 of the sorted pair of children,
+// its proofs are verified by util.VerifyMerkleProof.
+// The leaves are sorted, so the tree only depends on the set of leaves but not their order.
+// A node without sibling is promoted to the next layer as is.
+type Tree struct {
+	// layers[0] are the sorted leaves, the last layer is the root
+	layers [][][]byte
+}
+
+// NewTree builds the tree of the leaf hashes.
+func NewTree(leaves [][]byte) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyTree
+	}
+	layer := make([][]byte, len(leaves))
+	copy(layer, leaves)
+	sort.Slice(layer, func(i, j int) bool {
+		return bytes.Compare(layer[i], layer[j]) < 0
+	})
+	for i := 1; i < len(layer); i++ {
+		if bytes.Equal(layer[i-1], layer[i]) {
+			return nil, ErrDuplicateLeaf
+		}
+	}
+
+	layers := [][][]byte{layer}
+	for len(layer) > 1 {
+		next := make([][]byte, 0, (len(layer)+1)/2)
+		for i := 0; i < len(layer); i += 2 {
+			if i+1 == len(layer) {
+				next = append(next, layer[i])
+				continue
+			}
+			next = append(next, hashPair(layer[i], layer[i+1]))
+		}
+		layers = append(layers, next)
+		layer = next
+	}
+	return &Tree{layers: layers}, nil
+}
+
+// Root returns the root hash of the tree.
+func (t *Tree) Root() []byte {
+	return t.layers[len(t.layers)-1][0]
+}
+
+// Depth returns the number of layers above the leaves.
+func (t *Tree) Depth() int {
+	return len(t.layers) - 1
+}
+
+// Proof returns the sibling hashes from the leaf up to the root.
+func (t *Tree) Proof(leaf []byte) ([][]byte, error) {
+	leaves := t.layers[0]
+	index := sort.Search(len(leaves), func(i int) bool {
+		return bytes.Compare(leaves[i], leaf) >= 0
+	})
+	if index == len(leaves) || !bytes.Equal(leaves[index], leaf) {
+		return nil, ErrLeafNotFound
+	}
+
+	proof := make([][]byte, 0, t.Depth())
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		sibling := index ^ 1
+		if sibling < len(layer) {
+			proof = append(proof, layer[sibling])
+		}
+		index /= 2
+	}
+	return proof, nil
+}
+
+func hashPair(left, right []byte) []byte {
+	if bytes.Compare(left, right) < 0 {
+		return crypto.Keccak256(left, right)
+	}
+	return crypto.Keccak256(right, left)
+}
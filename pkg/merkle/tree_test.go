@@ -0,0 +1,86 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bnb-chain/token-recover-approver/pkg/util"
+)
+
+func makeLeaves(n int) [][]byte {
+	leaves := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		leaves = append(leaves, crypto.Keccak256([]byte(fmt.Sprintf("leaf-%d", i))))
+	}
+	return leaves
+}
+
+func TestTree_Proof(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 8, 13, 100} {
+		leaves := makeLeaves(n)
+		tree, err := NewTree(leaves)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, leaf := range leaves {
+			proof, err := tree.Proof(leaf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(proof) > tree.Depth() {
+				t.Errorf("n=%d leaf %d: proof length %d exceeds depth %d", n, i, len(proof), tree.Depth())
+			}
+			if !util.VerifyMerkleProof(tree.Root(), proof, leaf) {
+				t.Errorf("n=%d leaf %d: proof doesn't verify", n, i)
+			}
+		}
+	}
+}
+
+func TestTree_Root(t *testing.T) {
+	leaves := makeLeaves(7)
+	tree, err := NewTree(leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reversed := make([][]byte, 0, len(leaves))
+	for i := len(leaves) - 1; i >= 0; i-- {
+		reversed = append(reversed, leaves[i])
+	}
+	other, err := NewTree(reversed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tree.Root(), other.Root()) {
+		t.Errorf("root depends on the leaf order: %x != %x", tree.Root(), other.Root())
+	}
+
+	single, err := NewTree(leaves[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(single.Root(), leaves[0]) {
+		t.Errorf("root of a single leaf = %x, want the leaf %x", single.Root(), leaves[0])
+	}
+}
+
+func TestTree_Errors(t *testing.T) {
+	if _, err := NewTree(nil); !errors.Is(err, ErrEmptyTree) {
+		t.Errorf("expected ErrEmptyTree, got %v", err)
+	}
+	leaves := makeLeaves(3)
+	if _, err := NewTree(append(leaves, leaves[1])); !errors.Is(err, ErrDuplicateLeaf) {
+		t.Errorf("expected ErrDuplicateLeaf, got %v", err)
+	}
+	tree, err := NewTree(leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tree.Proof(crypto.Keccak256([]byte("missing"))); !errors.Is(err, ErrLeafNotFound) {
+		t.Errorf("expected ErrLeafNotFound, got %v", err)
+	}
+}